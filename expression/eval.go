@@ -0,0 +1,131 @@
+package expression
+
+import (
+	"math"
+
+	"github.com/hunyxv/datastructure/stack"
+)
+
+// Func 可在表达式中调用的函数
+type Func struct {
+	Arity int // 参数个数，小于 0 表示不定参数（至少一个）
+	Fn    func(args ...float64) float64
+}
+
+// Builtins 内置函数
+var Builtins = map[string]Func{
+	"abs":   {1, func(a ...float64) float64 { return math.Abs(a[0]) }},
+	"sqrt":  {1, func(a ...float64) float64 { return math.Sqrt(a[0]) }},
+	"exp":   {1, func(a ...float64) float64 { return math.Exp(a[0]) }},
+	"ln":    {1, func(a ...float64) float64 { return math.Log(a[0]) }},
+	"log":   {1, func(a ...float64) float64 { return math.Log10(a[0]) }},
+	"sin":   {1, func(a ...float64) float64 { return math.Sin(a[0]) }},
+	"cos":   {1, func(a ...float64) float64 { return math.Cos(a[0]) }},
+	"tan":   {1, func(a ...float64) float64 { return math.Tan(a[0]) }},
+	"floor": {1, func(a ...float64) float64 { return math.Floor(a[0]) }},
+	"ceil":  {1, func(a ...float64) float64 { return math.Ceil(a[0]) }},
+	"pow":   {2, func(a ...float64) float64 { return math.Pow(a[0], a[1]) }},
+	"min": {-1, func(a ...float64) float64 {
+		m := a[0]
+		for _, v := range a[1:] {
+			m = math.Min(m, v)
+		}
+		return m
+	}},
+	"max": {-1, func(a ...float64) float64 {
+		m := a[0]
+		for _, v := range a[1:] {
+			m = math.Max(m, v)
+		}
+		return m
+	}},
+}
+
+// Eval 使用给定的变量求值，函数从 Builtins 中查找
+func (e *Expression) Eval(vars map[string]float64) (float64, error) {
+	return e.EvalWith(vars, Builtins)
+}
+
+// EvalWith 使用给定的变量和函数求值
+//
+//	未定义的变量、函数，参数个数不符以及除数为 0 时返回 *Error
+func (e *Expression) EvalWith(vars map[string]float64, funcs map[string]Func) (float64, error) {
+	values := stack.NewGrowableStack[float64](len(e.postfix), nil)
+	for _, t := range e.postfix {
+		switch {
+		case t.Kind == Number:
+			values.Push(t.Value)
+
+		case t.Kind == Ident && t.call:
+			fn, ok := funcs[t.Text]
+			if !ok {
+				return 0, errorf(t.Pos, "undefined function %s", t.Text)
+			}
+			if (fn.Arity >= 0 && t.argc != fn.Arity) || (fn.Arity < 0 && t.argc == 0) {
+				return 0, errorf(t.Pos, "wrong number of arguments to %s: %d", t.Text, t.argc)
+			}
+			args := make([]float64, t.argc)
+			for i := t.argc - 1; i >= 0; i-- {
+				args[i], _ = values.Pop()
+			}
+			values.Push(fn.Fn(args...))
+
+		case t.Kind == Ident:
+			v, ok := vars[t.Text]
+			if !ok {
+				return 0, errorf(t.Pos, "undefined variable %s", t.Text)
+			}
+			values.Push(v)
+
+		case t.unary:
+			v, _ := values.Pop()
+			if t.Text == "-" {
+				v = -v
+			}
+			values.Push(v)
+
+		default:
+			b, _ := values.Pop()
+			a, _ := values.Pop()
+			v, err := apply(t, a, b)
+			if err != nil {
+				return 0, err
+			}
+			values.Push(v)
+		}
+	}
+	return values.Pop()
+}
+
+func apply(op Token, a, b float64) (float64, error) {
+	switch op.Text {
+	case "+":
+		return a + b, nil
+	case "-":
+		return a - b, nil
+	case "*":
+		return a * b, nil
+	case "/":
+		if b == 0 {
+			return 0, errorf(op.Pos, "division by zero")
+		}
+		return a / b, nil
+	case "%":
+		if b == 0 {
+			return 0, errorf(op.Pos, "division by zero")
+		}
+		return math.Mod(a, b), nil
+	case "^":
+		return math.Pow(a, b), nil
+	}
+	return 0, errorf(op.Pos, "unknown operator %s", op.Text)
+}
+
+// Eval 解析并求值表达式
+func Eval(src string, vars map[string]float64) (float64, error) {
+	e, err := Parse(src)
+	if err != nil {
+		return 0, err
+	}
+	return e.Eval(vars)
+}
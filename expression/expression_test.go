@@ -0,0 +1,110 @@
+package expression
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPostfix(t *testing.T) {
+	cases := map[string]string{
+		"1 + 2 * 3":         "1 2 3 * +",
+		"(1 + 2) * 3":       "1 2 + 3 *",
+		"2 ^ 3 ^ 2":         "2 3 2 ^ ^",
+		"10 - 4 - 3":        "10 4 - 3 -",
+		"-x ^ 2":            "x 2 ^ neg",
+		"-2 * 3":            "2 neg 3 *",
+		"max(a, b + 1, 2)":  "a b 1 + 2 max/3",
+		"sin(pi / 2) + f()": "pi 2 / sin/1 f/0 +",
+	}
+	for src, want := range cases {
+		e, err := Parse(src)
+		if err != nil {
+			t.Fatalf("Parse(%q): %v", src, err)
+		}
+		if got := e.Postfix(); got != want {
+			t.Errorf("Parse(%q).Postfix() = %q, want %q", src, got, want)
+		}
+	}
+}
+
+func TestEval(t *testing.T) {
+	vars := map[string]float64{"x": 3, "y": 4, "pi": math.Pi}
+	cases := map[string]float64{
+		"1 + 2 * 3":                7,
+		"(1 + 2) * 3":              9,
+		"2 ^ 3 ^ 2":                512,
+		"10 - 4 - 3":               3,
+		"-2 ^ 2":                   -4,
+		"2 ^ -1":                   0.5,
+		"--x":                      3,
+		"+x - -y":                  7,
+		"7 % 4":                    3,
+		"sqrt(x*x + y*y)":          5,
+		"max(x, y, 1) - min(x, y)": 1,
+		"pow(2, 10)":               1024,
+		"floor(sin(pi / 2))":       1,
+		"1.5e2 + .5":               150.5,
+	}
+	for src, want := range cases {
+		got, err := Eval(src, vars)
+		if err != nil {
+			t.Fatalf("Eval(%q): %v", src, err)
+		}
+		if math.Abs(got-want) > 1e-9 {
+			t.Errorf("Eval(%q) = %v, want %v", src, got, want)
+		}
+	}
+}
+
+func TestEvalWith(t *testing.T) {
+	e, err := Parse("double(n) + 1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	funcs := map[string]Func{"double": {1, func(a ...float64) float64 { return 2 * a[0] }}}
+	for n := 0.0; n < 3; n++ {
+		got, err := e.EvalWith(map[string]float64{"n": n}, funcs)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != 2*n+1 {
+			t.Fatalf("got %v, want %v", got, 2*n+1)
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	cases := []struct {
+		src string
+		pos int
+	}{
+		{"1 + $", 4},
+		{"1 +", 3},
+		{"* 2", 0},
+		{"(1 + 2", 0},
+		{"1 + 2)", 5},
+		{"1 2", 2},
+		{"2 (3)", 2},
+		{"()", 1},
+		{"f(1,)", 4},
+		{"(1, 2)", 2},
+		{"1.2.3", 0},
+		{"y + 1", 0},
+		{"nope(1)", 0},
+		{"sqrt(1, 2)", 0},
+		{"max()", 0},
+		{"1 / (x - x)", 2},
+	}
+	for _, c := range cases {
+		_, err := Eval(c.src, map[string]float64{"x": 1})
+		var perr *Error
+		if !errors.As(err, &perr) {
+			t.Errorf("Eval(%q) error = %v, want *Error", c.src, err)
+			continue
+		}
+		if perr.Pos != c.pos {
+			t.Errorf("Eval(%q) error at %d (%v), want %d", c.src, perr.Pos, perr, c.pos)
+		}
+	}
+}
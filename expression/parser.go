@@ -0,0 +1,203 @@
+package expression
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/hunyxv/datastructure/stack"
+)
+
+// Expression 解析后的表达式，以后缀（逆波兰）形式保存
+type Expression struct {
+	src     string
+	postfix []Token
+}
+
+// precedence 运算符优先级及结合性
+func precedence(t Token) (prec int, rightAssoc bool) {
+	if t.unary {
+		return 3, true
+	}
+	switch t.Text {
+	case "+", "-":
+		return 1, false
+	case "*", "/", "%":
+		return 2, false
+	case "^":
+		return 4, true
+	}
+	return 0, false
+}
+
+// Parse 解析中缀表达式
+//
+//	支持 + - * / % ^ 运算符（^ 为右结合的乘方）、一元正负号、括号、变量以及函数调用。
+//	解析失败时返回 *Error，其中包含出错的位置
+func Parse(src string) (*Expression, error) {
+	tokens, err := Tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+
+	postfix, err := shuntingYard(tokens, len(src))
+	if err != nil {
+		return nil, err
+	}
+	return &Expression{src: src, postfix: postfix}, nil
+}
+
+// shuntingYard 使用调度场算法将中缀记号转换为后缀记号
+func shuntingYard(tokens []Token, end int) ([]Token, error) {
+	var output []Token
+	ops := stack.NewGrowableStack[Token](len(tokens), nil)
+	argc := stack.NewGrowableStack[int](0, nil) // 每层函数调用已有的参数个数
+	expectOperand := true
+
+	// popUntilParen 将运算符出栈直到左括号，返回左括号
+	popUntilParen := func() (Token, bool) {
+		for {
+			top, err := ops.Pop()
+			if err != nil {
+				return Token{}, false
+			}
+			if top.Kind == LParen {
+				return top, true
+			}
+			output = append(output, top)
+		}
+	}
+
+	for i, t := range tokens {
+		switch t.Kind {
+		case Number:
+			if !expectOperand {
+				return nil, errorf(t.Pos, "unexpected number %s", t.Text)
+			}
+			output = append(output, t)
+			expectOperand = false
+
+		case Ident:
+			if !expectOperand {
+				return nil, errorf(t.Pos, "unexpected identifier %s", t.Text)
+			}
+			if i+1 < len(tokens) && tokens[i+1].Kind == LParen {
+				t.call = true
+				ops.Push(t)
+				continue
+			}
+			output = append(output, t)
+			expectOperand = false
+
+		case Operator:
+			if expectOperand {
+				if t.Text != "-" && t.Text != "+" {
+					return nil, errorf(t.Pos, "unexpected operator %s", t.Text)
+				}
+				t.unary = true
+				ops.Push(t)
+				continue
+			}
+			prec, rightAssoc := precedence(t)
+			for {
+				top, err := ops.Peek()
+				if err != nil || top.Kind != Operator {
+					break
+				}
+				topPrec, _ := precedence(top)
+				if topPrec < prec || (topPrec == prec && rightAssoc) {
+					break
+				}
+				ops.Pop()
+				output = append(output, top)
+			}
+			ops.Push(t)
+			expectOperand = true
+
+		case LParen:
+			if !expectOperand {
+				return nil, errorf(t.Pos, "unexpected '('")
+			}
+			if top, err := ops.Peek(); err == nil && top.Kind == Ident && top.call {
+				t.call = true
+				argc.Push(0)
+			}
+			ops.Push(t)
+
+		case Comma:
+			if expectOperand {
+				return nil, errorf(t.Pos, "missing argument before ','")
+			}
+			paren, ok := popUntilParen()
+			if !ok || !paren.call {
+				return nil, errorf(t.Pos, "unexpected ',' outside function call")
+			}
+			ops.Push(paren)
+			n, _ := argc.Pop()
+			argc.Push(n + 1)
+			expectOperand = true
+
+		case RParen:
+			// 只有函数调用允许空括号，如 f()
+			empty := expectOperand && i > 0 && tokens[i-1].Kind == LParen
+			if expectOperand && !empty {
+				return nil, errorf(t.Pos, "unexpected ')'")
+			}
+			paren, ok := popUntilParen()
+			if !ok {
+				return nil, errorf(t.Pos, "unmatched ')'")
+			}
+			if !paren.call {
+				if empty {
+					return nil, errorf(t.Pos, "empty parentheses")
+				}
+				expectOperand = false
+				continue
+			}
+			n, _ := argc.Pop()
+			if !empty {
+				n++
+			}
+			fn, _ := ops.Pop()
+			fn.argc = n
+			output = append(output, fn)
+			expectOperand = false
+		}
+	}
+
+	if expectOperand {
+		return nil, errorf(end, "unexpected end of expression")
+	}
+	for {
+		top, err := ops.Pop()
+		if err != nil {
+			return output, nil
+		}
+		if top.Kind == LParen {
+			return nil, errorf(top.Pos, "unmatched '('")
+		}
+		output = append(output, top)
+	}
+}
+
+// String 返回表达式的原文
+func (e *Expression) String() string {
+	return e.src
+}
+
+// Postfix 返回以空格分隔的后缀表达式，一元负号记为 neg，函数调用记为 name/参数个数
+func (e *Expression) Postfix() string {
+	parts := make([]string, len(e.postfix))
+	for i, t := range e.postfix {
+		switch {
+		case t.unary && t.Text == "-":
+			parts[i] = "neg"
+		case t.unary:
+			parts[i] = "pos"
+		case t.call:
+			parts[i] = t.Text + "/" + strconv.Itoa(t.argc)
+		default:
+			parts[i] = t.Text
+		}
+	}
+	return strings.Join(parts, " ")
+}
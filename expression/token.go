@@ -0,0 +1,131 @@
+package expression
+
+import (
+	"fmt"
+	"strconv"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind 记号类型
+type Kind int
+
+const (
+	// Number 数字
+	Number Kind = iota + 1
+	// Ident 变量名或函数名
+	Ident
+	// Operator 运算符
+	Operator
+	// LParen 左括号
+	LParen
+	// RParen 右括号
+	RParen
+	// Comma 逗号
+	Comma
+)
+
+// Token 记号
+type Token struct {
+	Kind  Kind
+	Text  string
+	Pos   int     // 在表达式中的字节偏移（从 0 开始）
+	Value float64 // Number 的值
+
+	unary bool // 一元正负号
+	call  bool // 函数调用（Ident）或函数调用的左括号（LParen）
+	argc  int  // 函数调用的参数个数
+}
+
+// Error 带位置的解析/求值错误
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("position %d: %s", e.Pos, e.Msg)
+}
+
+func errorf(pos int, format string, args ...any) error {
+	return &Error{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+const operators = "+-*/%^"
+
+// Tokenize 将中缀表达式切分为记号
+func Tokenize(src string) ([]Token, error) {
+	var tokens []Token
+	for pos := 0; pos < len(src); {
+		r, size := utf8.DecodeRuneInString(src[pos:])
+		switch {
+		case unicode.IsSpace(r):
+			pos += size
+		case r == '(':
+			tokens = append(tokens, Token{Kind: LParen, Text: "(", Pos: pos})
+			pos++
+		case r == ')':
+			tokens = append(tokens, Token{Kind: RParen, Text: ")", Pos: pos})
+			pos++
+		case r == ',':
+			tokens = append(tokens, Token{Kind: Comma, Text: ",", Pos: pos})
+			pos++
+		case r < utf8.RuneSelf && containsByte(operators, byte(r)):
+			tokens = append(tokens, Token{Kind: Operator, Text: string(r), Pos: pos})
+			pos++
+		case r == '.' || unicode.IsDigit(r):
+			end := scanNumber(src, pos)
+			v, err := strconv.ParseFloat(src[pos:end], 64)
+			if err != nil {
+				return nil, errorf(pos, "invalid number %q", src[pos:end])
+			}
+			tokens = append(tokens, Token{Kind: Number, Text: src[pos:end], Pos: pos, Value: v})
+			pos = end
+		case r == '_' || unicode.IsLetter(r):
+			end := pos
+			for end < len(src) {
+				r, size := utf8.DecodeRuneInString(src[end:])
+				if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+					break
+				}
+				end += size
+			}
+			tokens = append(tokens, Token{Kind: Ident, Text: src[pos:end], Pos: pos})
+			pos = end
+		default:
+			return nil, errorf(pos, "unexpected character %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+func containsByte(s string, c byte) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			return true
+		}
+	}
+	return false
+}
+
+// scanNumber 返回从 pos 开始的数字（含小数和指数部分）的结束位置
+func scanNumber(src string, pos int) int {
+	end := pos
+	digits := func() {
+		for end < len(src) && (src[end] >= '0' && src[end] <= '9' || src[end] == '.') {
+			end++
+		}
+	}
+	digits()
+	if end < len(src) && (src[end] == 'e' || src[end] == 'E') {
+		exp := end + 1
+		if exp < len(src) && (src[exp] == '+' || src[exp] == '-') {
+			exp++
+		}
+		if exp < len(src) && src[exp] >= '0' && src[exp] <= '9' {
+			end = exp
+			digits()
+		}
+	}
+	return end
+}
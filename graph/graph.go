@@ -0,0 +1,141 @@
+package graph
+
+import (
+	"errors"
+	"math"
+	"sync"
+
+	"github.com/hunyxv/datastructure/heap"
+)
+
+var (
+	// ErrVertexNotFound vertex not found
+	ErrVertexNotFound = errors.New("vertex not found")
+	// ErrNegativeWeight negative edge weight
+	ErrNegativeWeight = errors.New("negative edge weight")
+	// ErrNoPath no path between vertices
+	ErrNoPath = errors.New("no path between vertices")
+	// ErrDirected graph is directed
+	ErrDirected = errors.New("graph is directed")
+	// ErrNotFinite distance is not finite
+	ErrNotFinite = errors.New("distance is not finite")
+)
+
+// Edge 带权边
+type Edge struct {
+	From, To any
+	Weight   float64
+}
+
+// QueueFactory 创建算法内部使用的最小堆
+type QueueFactory func() heap.PriorityQueue
+
+var (
+	// BinaryHeapQueue 使用二叉堆
+	BinaryHeapQueue QueueFactory = func() heap.PriorityQueue {
+		return heap.NewBinaryHeap(heap.MinHeap)
+	}
+	// FibHeapQueue 使用斐波那契堆，decrease-key 的均摊复杂度为 O(1)
+	FibHeapQueue QueueFactory = func() heap.PriorityQueue {
+		return heap.NewFibHeap(heap.MinHeap)
+	}
+)
+
+// Graph 邻接表表示的带权图
+type Graph struct {
+	directed bool
+	vertices []any // 按加入顺序保存的顶点
+	adj      map[any][]Edge
+
+	mux sync.RWMutex
+}
+
+// NewGraph 创建图，directed 为 true 时为有向图
+func NewGraph(directed bool) *Graph {
+	return &Graph{
+		directed: directed,
+		adj:      make(map[any][]Edge),
+	}
+}
+
+// Directed 是否为有向图
+func (g *Graph) Directed() bool {
+	return g.directed
+}
+
+// AddVertex 添加顶点，顶点已存在时不做任何操作
+func (g *Graph) AddVertex(v any) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+	g.addVertex(v)
+}
+
+func (g *Graph) addVertex(v any) {
+	if _, ok := g.adj[v]; ok {
+		return
+	}
+	g.adj[v] = nil
+	g.vertices = append(g.vertices, v)
+}
+
+// AddEdge 添加一条边，顶点不存在时自动添加
+//	无向图会同时添加反向边
+func (g *Graph) AddEdge(from, to any, weight float64) {
+	g.mux.Lock()
+	defer g.mux.Unlock()
+
+	g.addVertex(from)
+	g.addVertex(to)
+	g.adj[from] = append(g.adj[from], Edge{From: from, To: to, Weight: weight})
+	if !g.directed && from != to {
+		g.adj[to] = append(g.adj[to], Edge{From: to, To: from, Weight: weight})
+	}
+}
+
+// HasVertex 是否存在顶点 v
+func (g *Graph) HasVertex(v any) bool {
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+	_, ok := g.adj[v]
+	return ok
+}
+
+// Vertices 按加入顺序返回全部顶点
+func (g *Graph) Vertices() []any {
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+	vertices := make([]any, len(g.vertices))
+	copy(vertices, g.vertices)
+	return vertices
+}
+
+// Edges 返回从 v 出发的全部边
+func (g *Graph) Edges(v any) []Edge {
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+	edges := make([]Edge, len(g.adj[v]))
+	copy(edges, g.adj[v])
+	return edges
+}
+
+// item 优先队列中的顶点，以当前距离作为排序指标
+type item struct {
+	v any
+	d float64
+}
+
+func (i *item) Key() any { return i.v }
+
+func (i *item) Value() float64 { return i.d }
+
+// push 插入顶点或更新其距离
+//	距离为 ±Inf 或 NaN 时返回 ErrNotFinite，保证不同的 QueueFactory 行为一致
+func push(pq heap.PriorityQueue, v any, d float64) error {
+	if math.IsInf(d, 0) || math.IsNaN(d) {
+		return ErrNotFinite
+	}
+	if pq.Contains(v) {
+		return pq.Update(&item{v: v, d: d})
+	}
+	return pq.Insert(&item{v: v, d: d})
+}
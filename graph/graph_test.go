@@ -0,0 +1,195 @@
+package graph
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var queues = map[string]QueueFactory{
+	"binary": BinaryHeapQueue,
+	"fib":    FibHeapQueue,
+}
+
+func newTestGraph(directed bool) *Graph {
+	g := NewGraph(directed)
+	g.AddEdge("a", "b", 7)
+	g.AddEdge("a", "c", 9)
+	g.AddEdge("a", "f", 14)
+	g.AddEdge("b", "c", 10)
+	g.AddEdge("b", "d", 15)
+	g.AddEdge("c", "d", 11)
+	g.AddEdge("c", "f", 2)
+	g.AddEdge("d", "e", 6)
+	g.AddEdge("e", "f", 9)
+	g.AddVertex("g")
+	return g
+}
+
+func TestShortestPaths(t *testing.T) {
+	g := newTestGraph(false)
+	want := map[any]float64{"a": 0, "b": 7, "c": 9, "d": 20, "e": 20, "f": 11}
+	for name, newQueue := range queues {
+		paths, err := g.ShortestPaths("a", newQueue)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for v, d := range want {
+			if got, ok := paths.Distance(v); !ok || got != d {
+				t.Fatalf("%s: %v: %f --> %f", name, v, d, got)
+			}
+		}
+		if _, ok := paths.Distance("g"); ok || paths.PathTo("g") != nil {
+			t.Fatalf("%s: g should be unreachable", name)
+		}
+
+		path := paths.PathTo("e")
+		expected := []any{"a", "c", "f", "e"}
+		if len(path) != len(expected) {
+			t.Fatalf("%s: path %v", name, path)
+		}
+		for i := range path {
+			if path[i] != expected[i] {
+				t.Fatalf("%s: path %v", name, path)
+			}
+		}
+	}
+
+	if _, err := g.ShortestPaths("x", nil); err != ErrVertexNotFound {
+		t.Fatalf("expected ErrVertexNotFound, got %v", err)
+	}
+	g.AddEdge("g", "a", -1)
+	if _, err := g.ShortestPaths("g", nil); err != ErrNegativeWeight {
+		t.Fatalf("expected ErrNegativeWeight, got %v", err)
+	}
+}
+
+func TestShortestPathsDirected(t *testing.T) {
+	g := newTestGraph(true)
+	paths, _ := g.ShortestPaths("c", nil)
+	if _, ok := paths.Distance("a"); ok {
+		t.Fatal("a should be unreachable from c")
+	}
+	if d, _ := paths.Distance("e"); d != 17 {
+		t.Fatalf("c --> e: %f", d)
+	}
+}
+
+func TestMinimumSpanningTree(t *testing.T) {
+	g := newTestGraph(false)
+	g.AddEdge("x", "y", 3)
+	for name, newQueue := range queues {
+		edges, total, err := g.MinimumSpanningTree(newQueue)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// a-b 7, a-c 9, c-f 2, d-e 6, e-f 9, x-y 3
+		if total != 36 || len(edges) != 6 {
+			t.Fatalf("%s: total %f, edges %v", name, total, edges)
+		}
+	}
+
+	if _, _, err := newTestGraph(true).MinimumSpanningTree(nil); err != ErrDirected {
+		t.Fatalf("expected ErrDirected, got %v", err)
+	}
+}
+
+// 距离不是有限值时两种队列都返回 ErrNotFinite，而不是静默丢弃顶点
+func TestNotFinite(t *testing.T) {
+	g := newTestGraph(false)
+	g.AddEdge("g", "h", math.Inf(1))
+	g.AddEdge("a", "g", math.NaN())
+	for name, newQueue := range queues {
+		if _, err := g.ShortestPaths("a", newQueue); err != ErrNotFinite {
+			t.Fatalf("%s: expected ErrNotFinite, got %v", name, err)
+		}
+		if _, _, err := g.AStar("g", "h", nil, newQueue); err != ErrNotFinite {
+			t.Fatalf("%s: expected ErrNotFinite, got %v", name, err)
+		}
+		if _, _, err := g.MinimumSpanningTree(newQueue); err != ErrNotFinite {
+			t.Fatalf("%s: expected ErrNotFinite, got %v", name, err)
+		}
+		infinite := func(any) float64 { return math.Inf(1) }
+		if _, _, err := g.AStar("a", "e", infinite, newQueue); err != ErrNotFinite {
+			t.Fatalf("%s: expected ErrNotFinite, got %v", name, err)
+		}
+	}
+}
+
+type cell struct{ x, y int }
+
+func TestAStar(t *testing.T) {
+	const size = 30
+	r := rand.New(rand.NewSource(1))
+	g := NewGraph(false)
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			// 随机设置障碍
+			if r.Intn(5) == 0 && x+y != 0 && x+y != 2*size-2 {
+				continue
+			}
+			g.AddVertex(cell{x, y})
+			if g.HasVertex(cell{x - 1, y}) {
+				g.AddEdge(cell{x - 1, y}, cell{x, y}, 1+r.Float64())
+			}
+			if g.HasVertex(cell{x, y - 1}) {
+				g.AddEdge(cell{x, y - 1}, cell{x, y}, 1+r.Float64())
+			}
+		}
+	}
+
+	source, target := cell{0, 0}, cell{size - 1, size - 1}
+	manhattan := func(v any) float64 {
+		c := v.(cell)
+		return math.Abs(float64(target.x-c.x)) + math.Abs(float64(target.y-c.y))
+	}
+
+	paths, _ := g.ShortestPaths(source, nil)
+	want, reachable := paths.Distance(target)
+	for name, newQueue := range queues {
+		path, cost, err := g.AStar(source, target, manhattan, newQueue)
+		if !reachable {
+			if err != ErrNoPath {
+				t.Fatalf("%s: expected ErrNoPath, got %v", name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(cost-want) > 1e-9 {
+			t.Fatalf("%s: cost %f --> %f", name, want, cost)
+		}
+		if path[0] != source || path[len(path)-1] != target {
+			t.Fatalf("%s: path %v", name, path)
+		}
+	}
+}
+
+// 可采纳但不一致的启发函数：c 第一次出队时的距离不是最短的，需要重新入队
+func TestAStarInconsistentHeuristic(t *testing.T) {
+	g := NewGraph(true)
+	g.AddEdge("s", "a", 1)
+	g.AddEdge("s", "b", 2)
+	g.AddEdge("a", "c", 3)
+	g.AddEdge("b", "c", 1)
+	g.AddEdge("c", "t", 3)
+	heuristic := func(v any) float64 {
+		if v == "b" {
+			return 4
+		}
+		return 0
+	}
+
+	for name, newQueue := range queues {
+		for _, h := range []func(any) float64{heuristic, nil} {
+			path, cost, err := g.AStar("s", "t", h, newQueue)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cost != 6 || len(path) != 4 || path[1] != "b" {
+				t.Fatalf("%s: cost %f, path %v", name, cost, path)
+			}
+		}
+	}
+}
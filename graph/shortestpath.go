@@ -0,0 +1,150 @@
+package graph
+
+import "math"
+
+// Paths 单源最短路径
+type Paths struct {
+	source any
+	dist   map[any]float64
+	prev   map[any]any
+}
+
+// Source 源点
+func (p *Paths) Source() any {
+	return p.source
+}
+
+// Distance 源点到 v 的最短距离，不可达时返回 +Inf 和 false
+func (p *Paths) Distance(v any) (float64, bool) {
+	d, ok := p.dist[v]
+	if !ok {
+		return math.Inf(1), false
+	}
+	return d, true
+}
+
+// PathTo 源点到 v 的最短路径（包含两端），不可达时返回 nil
+func (p *Paths) PathTo(v any) []any {
+	if _, ok := p.dist[v]; !ok {
+		return nil
+	}
+	return buildPath(p.prev, p.source, v)
+}
+
+// buildPath 根据前驱表还原 source 到 target 的路径
+func buildPath(prev map[any]any, source, target any) []any {
+	path := []any{target}
+	for v := target; v != source; {
+		v = prev[v]
+		path = append(path, v)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+	return path
+}
+
+// ShortestPaths 使用 Dijkstra 算法计算 source 到其余顶点的最短路径
+//	边权不能为负，距离为 ±Inf 或 NaN 时返回 ErrNotFinite；newQueue 为 nil 时使用 BinaryHeapQueue
+func (g *Graph) ShortestPaths(source any, newQueue QueueFactory) (*Paths, error) {
+	if newQueue == nil {
+		newQueue = BinaryHeapQueue
+	}
+
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+
+	if _, ok := g.adj[source]; !ok {
+		return nil, ErrVertexNotFound
+	}
+
+	paths := &Paths{
+		source: source,
+		dist:   map[any]float64{source: 0},
+		prev:   make(map[any]any),
+	}
+	visited := make(map[any]bool)
+	pq := newQueue()
+	push(pq, source, 0)
+	for {
+		top, err := pq.Pop()
+		if err != nil {
+			return paths, nil
+		}
+		u := top.Key()
+		visited[u] = true
+
+		for _, e := range g.adj[u] {
+			if e.Weight < 0 {
+				return nil, ErrNegativeWeight
+			}
+			if visited[e.To] {
+				continue
+			}
+			d := paths.dist[u] + e.Weight
+			if old, ok := paths.dist[e.To]; ok && old <= d {
+				continue
+			}
+			paths.dist[e.To] = d
+			paths.prev[e.To] = u
+			if err := push(pq, e.To, d); err != nil {
+				return nil, err
+			}
+		}
+	}
+}
+
+// AStar 使用 A* 算法搜索 source 到 target 的最短路径
+//	heuristic 为顶点到 target 的估计距离，不能高估实际距离，为 nil 时退化为 Dijkstra 算法；
+//	heuristic 不满足一致性（h(u) <= w(u, v) + h(v)）时，已出队的顶点找到更短的路径后会重新入队。
+//	边权不能为负，距离或估计距离为 ±Inf 或 NaN 时返回 ErrNotFinite；newQueue 为 nil 时使用 BinaryHeapQueue
+func (g *Graph) AStar(source, target any, heuristic func(v any) float64, newQueue QueueFactory) ([]any, float64, error) {
+	if newQueue == nil {
+		newQueue = BinaryHeapQueue
+	}
+	if heuristic == nil {
+		heuristic = func(any) float64 { return 0 }
+	}
+
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+
+	if _, ok := g.adj[source]; !ok {
+		return nil, 0, ErrVertexNotFound
+	}
+	if _, ok := g.adj[target]; !ok {
+		return nil, 0, ErrVertexNotFound
+	}
+
+	dist := map[any]float64{source: 0}
+	prev := make(map[any]any)
+	pq := newQueue()
+	if err := push(pq, source, heuristic(source)); err != nil {
+		return nil, 0, err
+	}
+	for {
+		top, err := pq.Pop()
+		if err != nil {
+			return nil, 0, ErrNoPath
+		}
+		u := top.Key()
+		if u == target {
+			return buildPath(prev, source, target), dist[target], nil
+		}
+
+		for _, e := range g.adj[u] {
+			if e.Weight < 0 {
+				return nil, 0, ErrNegativeWeight
+			}
+			d := dist[u] + e.Weight
+			if old, ok := dist[e.To]; ok && old <= d {
+				continue
+			}
+			dist[e.To] = d
+			prev[e.To] = u
+			if err := push(pq, e.To, d+heuristic(e.To)); err != nil {
+				return nil, 0, err
+			}
+		}
+	}
+}
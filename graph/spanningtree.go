@@ -0,0 +1,58 @@
+package graph
+
+// MinimumSpanningTree 使用 Prim 算法计算最小生成树
+//	图不连通时返回最小生成森林；只适用于无向图，边权为 ±Inf 或 NaN 时返回 ErrNotFinite；
+//	newQueue 为 nil 时使用 BinaryHeapQueue
+func (g *Graph) MinimumSpanningTree(newQueue QueueFactory) ([]Edge, float64, error) {
+	if newQueue == nil {
+		newQueue = BinaryHeapQueue
+	}
+
+	g.mux.RLock()
+	defer g.mux.RUnlock()
+
+	if g.directed {
+		return nil, 0, ErrDirected
+	}
+
+	var (
+		edges []Edge
+		total float64
+	)
+	inTree := make(map[any]bool)
+	best := make(map[any]Edge) // 连接树外顶点与树的最小边
+	for _, root := range g.vertices {
+		if inTree[root] {
+			continue
+		}
+
+		pq := newQueue()
+		push(pq, root, 0)
+		for {
+			top, err := pq.Pop()
+			if err != nil {
+				break
+			}
+			u := top.Key()
+			inTree[u] = true
+			if e, ok := best[u]; ok {
+				edges = append(edges, e)
+				total += e.Weight
+			}
+
+			for _, e := range g.adj[u] {
+				if inTree[e.To] {
+					continue
+				}
+				if old, ok := best[e.To]; ok && old.Weight <= e.Weight {
+					continue
+				}
+				best[e.To] = e
+				if err := push(pq, e.To, e.Weight); err != nil {
+					return nil, 0, err
+				}
+			}
+		}
+	}
+	return edges, total, nil
+}
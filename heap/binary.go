@@ -2,6 +2,7 @@ package heap
 
 import (
 	"errors"
+	"sort"
 	"sync"
 )
 
@@ -20,6 +21,12 @@ var (
 	ErrEmpty = errors.New("heap is empty")
 	// ErrExceed index out of range
 	ErrExceed = errors.New("index out of range")
+	// ErrNotFound key not found
+	ErrNotFound = errors.New("key not found")
+	// ErrDuplicateKey duplicate key is not allowed
+	ErrDuplicateKey = errors.New("duplicate key is not allowed")
+	// ErrInfinity infinity is for internal use only
+	ErrInfinity = errors.New("infinity is for internal use only")
 )
 
 // Interface 用于两个 Interface 比较
@@ -28,78 +35,165 @@ type Interface interface {
 	Value() float64 // 排序指标
 }
 
+// entry 堆中的一个元素
+type entry struct {
+	value Interface
+	key   any
+	v     float64
+
+	pos  int    // 在 heap 中的下标
+	slot int    // 在 index[key] 中的下标
+	seq  uint64 // 插入序号
+}
+
 // BinaryHeap .
+//	允许多个元素拥有相同的 key，此时 Update/Delete 作用于其中最早插入的一个
 type BinaryHeap struct {
-	heap []Interface
+	heap  []*entry
+	index map[any][]*entry
 
-	t   T
-	mux sync.RWMutex
+	t      T
+	stable bool   // 排序指标相同时按插入顺序出堆
+	seq    uint64 // 下一个插入序号
+	mux    sync.RWMutex
 }
 
 // NewBinaryHeap 创建堆
 func NewBinaryHeap(t T) *BinaryHeap {
 	return &BinaryHeap{
-		heap: make([]Interface, 0),
-		t:    t,
+		heap:  make([]*entry, 0),
+		index: make(map[any][]*entry),
+		t:     t,
 	}
 }
 
+// NewStableBinaryHeap 创建稳定的堆
+//	排序指标相同的元素按插入顺序（FIFO）出堆，Update/Replace 不改变元素的插入顺序
+func NewStableBinaryHeap(t T) *BinaryHeap {
+	heap := NewBinaryHeap(t)
+	heap.stable = true
+	return heap
+}
+
+// T heap 的类型
+//	最小堆/最大堆
+func (h *BinaryHeap) T() T {
+	return h.t
+}
+
+// before a 是否应排在 b 之前
+func (h *BinaryHeap) before(a, b *entry) bool {
+	if h.stable && a.v == b.v {
+		return a.seq < b.seq
+	}
+	if h.t == MaxHeap {
+		return a.v > b.v
+	}
+	return a.v < b.v
+}
+
+func (h *BinaryHeap) swap(i, j int) {
+	h.heap[i], h.heap[j] = h.heap[j], h.heap[i]
+	h.heap[i].pos = i
+	h.heap[j].pos = j
+}
+
 func (h *BinaryHeap) shiftUp(index int) {
-	switch h.t {
-	case MaxHeap:
-		for h.heap[(index-1)/2].Value() < h.heap[index].Value() {
-			h.heap[(index-1)/2], h.heap[index] = h.heap[index], h.heap[(index-1)/2]
-			index = (index - 1) / 2
-		}
-	case MinHeap:
-		for h.heap[(index-1)/2].Value() > h.heap[index].Value() {
-			h.heap[(index-1)/2], h.heap[index] = h.heap[index], h.heap[(index-1)/2]
-			index = (index - 1) / 2
+	for index > 0 {
+		parent := (index - 1) / 2
+		if !h.before(h.heap[index], h.heap[parent]) {
+			return
 		}
+		h.swap(parent, index)
+		index = parent
 	}
 }
 
 func (h *BinaryHeap) shiftDown(index int) {
 	left := index*2 + 1
 	right := index*2 + 2
-	var target int
 	for left < len(h.heap) {
-		switch h.t {
-		case MaxHeap:
-			if right < len(h.heap) && ge(h.heap[right], h.heap[left]) {
-				target = right
-			} else {
-				target = left
-			}
-			if h.heap[index].Value() > h.heap[target].Value() {
-				return
-			}
-		case MinHeap:
-			if right < len(h.heap) && le(h.heap[right], h.heap[left]) {
-				target = right
-			} else {
-				target = left
-			}
-			if h.heap[index].Value() < h.heap[target].Value() {
-				return
-			}
+		target := left
+		if right < len(h.heap) && h.before(h.heap[right], h.heap[left]) {
+			target = right
+		}
+		if !h.before(h.heap[target], h.heap[index]) {
+			return
 		}
-		h.heap[index], h.heap[target] = h.heap[target], h.heap[index]
+		h.swap(index, target)
 		index = target
 		left = index*2 + 1
 		right = index*2 + 2
 	}
 }
 
+func (h *BinaryHeap) addIndex(e *entry) {
+	e.slot = len(h.index[e.key])
+	h.index[e.key] = append(h.index[e.key], e)
+}
+
+func (h *BinaryHeap) removeIndex(e *entry) {
+	entries := h.index[e.key]
+	last := len(entries) - 1
+	entries[e.slot] = entries[last]
+	entries[e.slot].slot = e.slot
+	entries[last] = nil
+	if last == 0 {
+		delete(h.index, e.key)
+		return
+	}
+	h.index[e.key] = entries[:last]
+}
+
+// earliest 返回 key 相同的元素中最早插入的一个
+func earliest(entries []*entry) *entry {
+	e := entries[0]
+	for _, c := range entries[1:] {
+		if c.seq < e.seq {
+			e = c
+		}
+	}
+	return e
+}
+
+func (h *BinaryHeap) push(val Interface) {
+	e := &entry{
+		value: val,
+		key:   val.Key(),
+		v:     val.Value(),
+		pos:   len(h.heap),
+		seq:   h.seq,
+	}
+	h.seq++
+	h.heap = append(h.heap, e)
+	h.addIndex(e)
+	h.shiftUp(e.pos)
+}
+
+// removeAt 删除 index 位置的元素
+func (h *BinaryHeap) removeAt(index int) *entry {
+	e := h.heap[index]
+	last := len(h.heap) - 1
+	if index != last {
+		h.swap(index, last)
+	}
+	h.heap[last] = nil
+	h.heap = h.heap[:last]
+	h.removeIndex(e)
+	if index < last {
+		h.shiftDown(index)
+		h.shiftUp(index)
+	}
+	return e
+}
+
 // Insert 入堆
-func (h *BinaryHeap) Insert(val Interface) {
+func (h *BinaryHeap) Insert(val Interface) error {
 	h.mux.Lock()
 	defer h.mux.Unlock()
 
-	h.heap = append(h.heap, val)
-	index := len(h.heap) - 1
-
-	h.shiftUp(index)
+	h.push(val)
+	return nil
 }
 
 // Pop 返回堆顶元素并删除
@@ -111,12 +205,7 @@ func (h *BinaryHeap) Pop() (val Interface, err error) {
 		return nil, ErrEmpty
 	}
 
-	val = h.heap[0]
-	h.heap[0] = h.heap[len(h.heap)-1]
-	h.heap = h.heap[:len(h.heap)-1]
-
-	h.shiftDown(0)
-	return
+	return h.removeAt(0).value, nil
 }
 
 // Peek 返回堆顶元素不删除
@@ -126,7 +215,7 @@ func (h *BinaryHeap) Peek() (val Interface, err error) {
 	if len(h.heap) == 0 {
 		return nil, ErrEmpty
 	}
-	val = h.heap[0]
+	val = h.heap[0].value
 	return
 }
 
@@ -135,15 +224,11 @@ func (h *BinaryHeap) PopByIndex(index int) (val Interface, err error) {
 	h.mux.Lock()
 	defer h.mux.Unlock()
 
-	if index >= len(h.heap) {
+	if index < 0 || index >= len(h.heap) {
 		return nil, ErrExceed
 	}
 
-	val = h.heap[index]
-	h.heap[index] = h.heap[len(h.heap)-1]
-	h.heap = h.heap[:len(h.heap)-1]
-	h.shiftDown(index)
-	return
+	return h.removeAt(index).value, nil
 }
 
 // Replace 替换 index 位置的值
@@ -151,27 +236,159 @@ func (h *BinaryHeap) Replace(index int, val Interface) error {
 	h.mux.Lock()
 	defer h.mux.Unlock()
 
-	if index >= len(h.heap) {
+	if index < 0 || index >= len(h.heap) {
 		return ErrExceed
 	}
 
-	h.heap[index] = val
+	e := h.heap[index]
+	h.removeIndex(e)
+	e.value, e.key, e.v = val, val.Key(), val.Value()
+	h.addIndex(e)
 	h.shiftUp(index)
-	h.shiftDown(index)
+	h.shiftDown(e.pos)
 	return nil
 }
 
-// Size .
-func (h *BinaryHeap) Size() int {
+// Update 根据元素的 key 更新其值
+func (h *BinaryHeap) Update(val Interface) error {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	entries, ok := h.index[val.Key()]
+	if !ok {
+		return ErrNotFound
+	}
+
+	e := earliest(entries)
+	e.value, e.v = val, val.Value()
+	h.shiftUp(e.pos)
+	h.shiftDown(e.pos)
+	return nil
+}
+
+// Delete 根据 key 删除元素，key 不存在时返回 nil
+func (h *BinaryHeap) Delete(key any) Interface {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	entries, ok := h.index[key]
+	if !ok {
+		return nil
+	}
+	return h.removeAt(earliest(entries).pos).value
+}
+
+// Contains 堆中是否存在 key
+func (h *BinaryHeap) Contains(key any) bool {
 	h.mux.RLock()
 	defer h.mux.RUnlock()
-	return len(h.heap)
+
+	_, ok := h.index[key]
+	return ok
 }
 
-func le(a, b Interface) bool {
-	return a.Value() <= b.Value()
+// Merge 将 other 中的元素全部移入 h，other 会被清空
+func (h *BinaryHeap) Merge(other PriorityQueue) error {
+	if other == PriorityQueue(h) {
+		return nil
+	}
+
+	values := drain(other)
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	for _, val := range values {
+		h.push(val)
+	}
+	return nil
 }
 
-func ge(a, b Interface) bool {
-	return a.Value() >= b.Value()
+// values 以数组顺序返回全部元素，调用方需持有锁
+func (h *BinaryHeap) values() []Interface {
+	values := make([]Interface, len(h.heap))
+	for i, e := range h.heap {
+		values[i] = e.value
+	}
+	return values
+}
+
+// take 按插入顺序取出全部元素并清空堆
+func (h *BinaryHeap) take() []Interface {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	sort.Slice(h.heap, func(i, j int) bool {
+		return h.heap[i].seq < h.heap[j].seq
+	})
+	values := h.values()
+	h.heap = make([]*entry, 0)
+	h.index = make(map[any][]*entry)
+	return values
+}
+
+// Range 以任意顺序对每个元素调用 f，f 返回 false 时停止
+//	f 中不能修改堆
+func (h *BinaryHeap) Range(f func(val Interface) bool) {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	for _, e := range h.heap {
+		if !f(e.value) {
+			return
+		}
+	}
+}
+
+// Drain 按出堆顺序取出全部元素，堆被清空
+func (h *BinaryHeap) Drain() []Interface {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	values := make([]Interface, 0, len(h.heap))
+	for len(h.heap) > 0 {
+		values = append(values, h.removeAt(0).value)
+	}
+	return values
+}
+
+// Sorted 按出堆顺序返回全部元素，不修改堆
+//	持锁期间复制元素，排序时不读取可能被 Update 修改的字段
+func (h *BinaryHeap) Sorted() []Interface {
+	h.mux.RLock()
+	entries := make([]entry, len(h.heap))
+	for i, e := range h.heap {
+		entries[i] = *e
+	}
+	h.mux.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return h.before(&entries[i], &entries[j])
+	})
+	values := make([]Interface, len(entries))
+	for i, e := range entries {
+		values[i] = e.value
+	}
+	return values
+}
+
+// Clone 复制一个结构完全相同的堆
+func (h *BinaryHeap) Clone() *BinaryHeap {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	heap := NewBinaryHeap(h.t)
+	heap.stable, heap.seq = h.stable, h.seq
+	heap.heap = make([]*entry, len(h.heap))
+	for i, e := range h.heap {
+		c := *e
+		heap.heap[i] = &c
+		heap.addIndex(&c)
+	}
+	return heap
+}
+
+// Size .
+func (h *BinaryHeap) Size() int {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	return len(h.heap)
 }
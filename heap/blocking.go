@@ -0,0 +1,143 @@
+package heap
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed queue is closed
+var ErrClosed = errors.New("queue is closed")
+
+// signal 唤醒一个等待者的通知
+//	容量为 1，通知不会阻塞；没有等待者时保留一次通知，交给下一个等待者
+type signal chan struct{}
+
+func newSignal() signal {
+	return make(signal, 1)
+}
+
+// notify 唤醒一个等待者
+func (s signal) notify() {
+	select {
+	case s <- struct{}{}:
+	default:
+	}
+}
+
+// BlockingPriorityQueue 阻塞优先队列
+//	队列为空时 Pop 阻塞直到有元素或 ctx 被取消；设置了容量时，队列满时 Push 阻塞。
+//	被包装的堆只能通过 BlockingPriorityQueue 访问
+type BlockingPriorityQueue struct {
+	pq       PriorityQueue
+	capacity int
+
+	closed   bool
+	done     chan struct{} // 队列关闭时被关闭，用于唤醒所有等待者
+	notEmpty signal        // 队列非空时唤醒一个 Pop
+	notFull  signal        // 队列未满时唤醒一个 Push
+	mux      sync.Mutex
+}
+
+// NewBlockingPriorityQueue 创建阻塞优先队列
+//	capacity <= 0 时不限制容量
+func NewBlockingPriorityQueue(pq PriorityQueue, capacity int) *BlockingPriorityQueue {
+	return &BlockingPriorityQueue{
+		pq:       pq,
+		capacity: capacity,
+		done:     make(chan struct{}),
+		notEmpty: newSignal(),
+		notFull:  newSignal(),
+	}
+}
+
+func (q *BlockingPriorityQueue) full() bool {
+	return q.capacity > 0 && q.pq.Size() >= q.capacity
+}
+
+// Push 插入一个元素，队列已满时阻塞
+//	队列关闭后返回 ErrClosed，ctx 被取消时返回 ctx.Err()
+func (q *BlockingPriorityQueue) Push(ctx context.Context, val Interface) error {
+	for {
+		q.mux.Lock()
+		if q.closed {
+			q.mux.Unlock()
+			return ErrClosed
+		}
+		if !q.full() {
+			err := q.pq.Insert(val)
+			if err == nil {
+				q.notEmpty.notify()
+			}
+			// 多次 Pop 的通知可能被合并为一次，仍有空位时继续唤醒下一个 Push
+			if !q.full() {
+				q.notFull.notify()
+			}
+			q.mux.Unlock()
+			return err
+		}
+		q.mux.Unlock()
+
+		select {
+		case <-q.notFull:
+		case <-q.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Pop 返回并移除堆顶元素，队列为空时阻塞
+//	队列关闭后仍可取出剩余元素，取完后返回 ErrClosed；ctx 被取消时返回 ctx.Err()
+func (q *BlockingPriorityQueue) Pop(ctx context.Context) (Interface, error) {
+	for {
+		q.mux.Lock()
+		if val, err := q.pq.Pop(); err == nil {
+			q.notFull.notify()
+			// 多次 Push 的通知可能被合并为一次，仍有元素时继续唤醒下一个 Pop
+			if q.pq.Size() > 0 {
+				q.notEmpty.notify()
+			}
+			q.mux.Unlock()
+			return val, nil
+		}
+		if q.closed {
+			q.mux.Unlock()
+			return nil, ErrClosed
+		}
+		q.mux.Unlock()
+
+		select {
+		case <-q.notEmpty:
+		case <-q.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Peek 返回堆顶元素（不移除）
+func (q *BlockingPriorityQueue) Peek() (Interface, error) {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	return q.pq.Peek()
+}
+
+// Size 元素个数
+func (q *BlockingPriorityQueue) Size() int {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	return q.pq.Size()
+}
+
+// Close 关闭队列并唤醒所有等待者
+//	关闭后 Push 返回 ErrClosed，Pop 在取完剩余元素后返回 ErrClosed
+func (q *BlockingPriorityQueue) Close() {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	close(q.done)
+}
@@ -0,0 +1,152 @@
+package heap
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlockingPriorityQueuePop(t *testing.T) {
+	q := NewBlockingPriorityQueue(NewBinaryHeap(MinHeap), 0)
+
+	result := make(chan Interface)
+	go func() {
+		val, err := q.Pop(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		result <- val
+	}()
+
+	time.Sleep(10 * time.Millisecond)
+	q.Push(context.Background(), value(3))
+	if val := <-result; val.Value() != 3 {
+		t.Fatalf("pop: %f", val.Value())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Pop(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestBlockingPriorityQueuePush(t *testing.T) {
+	q := NewBlockingPriorityQueue(NewBinaryHeap(MaxHeap), 2)
+	ctx := context.Background()
+	q.Push(ctx, value(1))
+	q.Push(ctx, value(2))
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := q.Push(timeout, value(3)); err != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- q.Push(ctx, value(3))
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if val, _ := q.Pop(ctx); val.Value() != 2 {
+		t.Fatalf("pop: %f", val.Value())
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if q.Size() != 2 {
+		t.Fatalf("size: %d", q.Size())
+	}
+}
+
+func TestBlockingPriorityQueueClose(t *testing.T) {
+	q := NewBlockingPriorityQueue(NewFibHeap(MinHeap), 0)
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := q.Pop(context.Background()); err != ErrClosed {
+				t.Errorf("expected ErrClosed, got %v", err)
+			}
+		}()
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+	wg.Wait()
+
+	if err := q.Push(context.Background(), value(1)); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+func TestBlockingPriorityQueueWorkers(t *testing.T) {
+	q := NewBlockingPriorityQueue(NewBinaryHeap(MinHeap), 8)
+	ctx := context.Background()
+
+	var mux sync.Mutex
+	count := 0
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				if _, err := q.Pop(ctx); err != nil {
+					return
+				}
+				mux.Lock()
+				count++
+				mux.Unlock()
+			}
+		}()
+	}
+
+	for i := 0; i < 1000; i++ {
+		if err := q.Push(ctx, value(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	q.Close()
+	wg.Wait()
+	if count != 1000 {
+		t.Fatalf("count: %d", count)
+	}
+}
+
+// 每次只唤醒一个等待者时，所有等待中的 Pop 都要被依次唤醒
+func TestBlockingPriorityQueueHandoff(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	for _, capacity := range []int{0, 2} {
+		q := NewBlockingPriorityQueue(NewBinaryHeap(MinHeap), capacity)
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+
+		const workers = 8
+		wg := new(sync.WaitGroup)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if _, err := q.Pop(ctx); err != nil {
+					t.Error(err)
+				}
+			}()
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		for i := 0; i < workers; i++ {
+			if err := q.Push(ctx, value(i)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		wg.Wait()
+		cancel()
+		if q.Size() != 0 {
+			t.Fatalf("size: %d", q.Size())
+		}
+	}
+}
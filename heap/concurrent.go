@@ -0,0 +1,162 @@
+package heap
+
+import (
+	"math"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// shard ConcurrentPriorityQueue 的一个分片
+type shard struct {
+	mux  sync.Mutex
+	heap *plainHeap // 只在持有 mux 时访问
+
+	top  uint64 // 堆顶排序指标的位表示，空分片为 NaN，无锁读取
+	size int64
+
+	_ [32]byte // 避免相邻分片伪共享
+}
+
+// updateTop 更新堆顶提示，调用方需持有锁
+func (s *shard) updateTop() {
+	top := math.NaN()
+	if e := s.heap.top(); e != nil {
+		top = e.v
+	}
+	atomic.StoreUint64(&s.top, math.Float64bits(top))
+	atomic.StoreInt64(&s.size, int64(s.heap.len()))
+}
+
+func (s *shard) loadTop() float64 {
+	return math.Float64frombits(atomic.LoadUint64(&s.top))
+}
+
+// ConcurrentPriorityQueue 分片的并发优先队列（MultiQueue）
+//	元素被插入到随机的分片中，Pop 随机选取两个分片并弹出其中较优的堆顶。
+//	各分片互不阻塞，吞吐量随分片数增长，但出堆顺序是松弛的：
+//	Pop 返回的元素不一定是全局最优，只保证是某个分片的堆顶，
+//	且在期望意义上与全局最优元素的排名差为 O(分片数)。
+//	单线程依次 Insert 再 Pop 时同样不保证严格有序
+type ConcurrentPriorityQueue struct {
+	shards []*shard
+	t      T
+	rand   sync.Pool // 每个 P 各自的随机数状态
+}
+
+// NewConcurrentPriorityQueue 创建并发优先队列
+//	shards <= 0 时使用 2 * GOMAXPROCS 个分片
+func NewConcurrentPriorityQueue(t T, shards int) *ConcurrentPriorityQueue {
+	if shards <= 0 {
+		shards = 2 * runtime.GOMAXPROCS(0)
+	}
+	q := &ConcurrentPriorityQueue{
+		shards: make([]*shard, shards),
+		t:      t,
+	}
+	for i := range q.shards {
+		q.shards[i] = &shard{heap: newPlainHeap(byValue(t))}
+		q.shards[i].updateTop()
+	}
+
+	var seed uint64
+	q.rand.New = func() any {
+		state := atomic.AddUint64(&seed, 0x9e3779b97f4a7c15)
+		return &state
+	}
+	return q
+}
+
+// random 返回一个随机分片的下标（xorshift64*）
+func (q *ConcurrentPriorityQueue) random() (int, int) {
+	state := q.rand.Get().(*uint64)
+	x := *state
+	if x == 0 {
+		x = 0x9e3779b97f4a7c15
+	}
+	x ^= x >> 12
+	x ^= x << 25
+	x ^= x >> 27
+	*state = x
+	q.rand.Put(state)
+
+	r := x * 0x2545f4914f6cdd1d
+	n := uint64(len(q.shards))
+	return int(r % n), int((r >> 32) % n)
+}
+
+// better a 是否优于 b，NaN（空分片）劣于任何值
+func (q *ConcurrentPriorityQueue) better(a, b float64) bool {
+	if math.IsNaN(b) {
+		return !math.IsNaN(a)
+	}
+	if q.t == MaxHeap {
+		return a > b
+	}
+	return a < b
+}
+
+// Insert 插入一个元素
+func (q *ConcurrentPriorityQueue) Insert(val Interface) {
+	i, _ := q.random()
+	s := q.shards[i]
+	// 分片被占用时换一个分片，多次失败后阻塞等待
+	for try := 0; !s.mux.TryLock(); try++ {
+		if try == len(q.shards) {
+			s.mux.Lock()
+			break
+		}
+		i, _ = q.random()
+		s = q.shards[i]
+	}
+	s.heap.push(&entry{value: val, v: val.Value()})
+	s.updateTop()
+	s.mux.Unlock()
+}
+
+// Pop 弹出一个近似最优的元素
+//	所有分片都为空时返回 ErrEmpty
+func (q *ConcurrentPriorityQueue) Pop() (Interface, error) {
+	for try := 0; try < len(q.shards); try++ {
+		i, j := q.random()
+		if q.better(q.shards[j].loadTop(), q.shards[i].loadTop()) {
+			i = j
+		}
+		s := q.shards[i]
+		if math.IsNaN(s.loadTop()) || !s.mux.TryLock() {
+			continue
+		}
+		if val, ok := q.popShard(s); ok {
+			return val, nil
+		}
+	}
+
+	// 随机选取失败，依次扫描所有分片
+	for _, s := range q.shards {
+		s.mux.Lock()
+		if val, ok := q.popShard(s); ok {
+			return val, nil
+		}
+	}
+	return nil, ErrEmpty
+}
+
+// popShard 弹出分片的堆顶并释放锁，调用方需持有锁
+func (q *ConcurrentPriorityQueue) popShard(s *shard) (Interface, bool) {
+	defer s.mux.Unlock()
+	if s.heap.len() == 0 {
+		return nil, false
+	}
+	val := s.heap.pop().value
+	s.updateTop()
+	return val, true
+}
+
+// Size 元素个数，并发修改时为近似值
+func (q *ConcurrentPriorityQueue) Size() int {
+	var size int64
+	for _, s := range q.shards {
+		size += atomic.LoadInt64(&s.size)
+	}
+	return int(size)
+}
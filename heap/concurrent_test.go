@@ -0,0 +1,111 @@
+package heap
+
+import (
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConcurrentPriorityQueue(t *testing.T) {
+	q := NewConcurrentPriorityQueue(MinHeap, 0)
+	const producers, count = 8, 2000
+
+	wg := new(sync.WaitGroup)
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				q.Insert(&TValue{key: int64(p*count + i), val: rand.Float64()})
+			}
+		}(p)
+	}
+	wg.Wait()
+	if q.Size() != producers*count {
+		t.Fatalf("size: %d", q.Size())
+	}
+
+	var popped sync.Map
+	var total int64
+	for c := 0; c < 4; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				val, err := q.Pop()
+				if err != nil {
+					return
+				}
+				if _, dup := popped.LoadOrStore(val.Key(), struct{}{}); dup {
+					t.Errorf("key %v popped twice", val.Key())
+				}
+				atomic.AddInt64(&total, 1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if total != producers*count || q.Size() != 0 {
+		t.Fatalf("popped %d, size %d", total, q.Size())
+	}
+}
+
+func TestConcurrentPriorityQueueRelaxed(t *testing.T) {
+	// 单分片时退化为严格有序的堆
+	q := NewConcurrentPriorityQueue(MaxHeap, 1)
+	for _, val := range []value{79, 66, 43, 83, 30, 87} {
+		q.Insert(val)
+	}
+	for _, want := range []float64{87, 83, 79, 66, 43, 30} {
+		if val, _ := q.Pop(); val.Value() != want {
+			t.Fatalf("%f --> %f", want, val.Value())
+		}
+	}
+
+	// 多分片时弹出的元素应接近最优
+	q = NewConcurrentPriorityQueue(MinHeap, 4)
+	const n = 10000
+	for i := 0; i < n; i++ {
+		q.Insert(value(rand.Intn(n)))
+	}
+	var sum float64
+	for i := 0; i < n/10; i++ {
+		val, _ := q.Pop()
+		sum += val.Value()
+	}
+	// 严格有序时前 10% 的均值约为 n/20
+	if avg := sum / (n / 10); avg > n/5 {
+		t.Fatalf("average of first 10%% is %f", avg)
+	}
+	if _, err := NewConcurrentPriorityQueue(MinHeap, 2).Pop(); err != ErrEmpty {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+}
+
+// benchmarkQueue 并行执行交替的 Insert 和 Pop
+func benchmarkQueue(b *testing.B, insert func(Interface), pop func()) {
+	for i := 0; i < 1000; i++ {
+		insert(value(rand.Intn(1000)))
+	}
+	b.SetParallelism(4)
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(rand.Int63()))
+		for pb.Next() {
+			insert(value(r.Intn(1000)))
+			pop()
+		}
+	})
+}
+
+func BenchmarkBinaryHeapParallel(b *testing.B) {
+	h := NewBinaryHeap(MinHeap)
+	benchmarkQueue(b, func(val Interface) { h.Insert(val) }, func() { h.Pop() })
+}
+
+func BenchmarkConcurrentPriorityQueueParallel(b *testing.B) {
+	q := NewConcurrentPriorityQueue(MinHeap, 2*runtime.GOMAXPROCS(0))
+	benchmarkQueue(b, q.Insert, func() { q.Pop() })
+}
@@ -0,0 +1,221 @@
+package heap
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock 时钟，用于替换 DelayQueue 的时间来源
+type Clock interface {
+	Now() time.Time
+	// NewTimer 创建一个 d 之后触发的定时器，stop 与 time.Timer.Stop 语义相同
+	NewTimer(d time.Duration) (c <-chan time.Time, stop func() bool)
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	t := time.NewTimer(d)
+	return t.C, t.Stop
+}
+
+// Delayed 延迟队列中的元素
+type Delayed interface {
+	Key() any // 唯一标识
+}
+
+// delayItem 以到期时间作为排序指标
+type delayItem struct {
+	val Delayed
+	at  time.Time
+	v   float64
+}
+
+func (d *delayItem) Key() any { return d.val.Key() }
+
+func (d *delayItem) Value() float64 { return d.v }
+
+// DelayQueue 延迟队列
+//	元素到期之后才能被 Take 取出，到期时间越早越先被取出
+type DelayQueue struct {
+	heap  *BinaryHeap
+	clock Clock
+	base  time.Time // 排序指标以 base 为起点，避免 UnixNano 转 float64 丢失精度
+
+	closed    bool
+	done      chan struct{} // 队列关闭时被关闭，用于唤醒所有等待者
+	leader    bool          // 是否有 Take 正在用定时器等待堆顶到期
+	reset     signal        // 堆顶变化时唤醒 leader 重新设置定时器
+	available signal        // 没有 leader 时唤醒一个 Take 成为 leader
+	mux       sync.Mutex
+}
+
+// NewDelayQueue 创建延迟队列
+//	clock 为 nil 时使用系统时钟
+func NewDelayQueue(clock Clock) *DelayQueue {
+	if clock == nil {
+		clock = systemClock{}
+	}
+	return &DelayQueue{
+		heap:      NewBinaryHeap(MinHeap),
+		clock:     clock,
+		base:      clock.Now(),
+		done:      make(chan struct{}),
+		reset:     newSignal(),
+		available: newSignal(),
+	}
+}
+
+func (q *DelayQueue) newItem(val Delayed, at time.Time) *delayItem {
+	return &delayItem{val: val, at: at, v: float64(at.Sub(q.base))}
+}
+
+// head 返回堆顶元素，队列为空时返回 nil，调用方需持有锁
+func (q *DelayQueue) head() *delayItem {
+	top, err := q.heap.Peek()
+	if err != nil {
+		return nil
+	}
+	return top.(*delayItem)
+}
+
+// changed 堆顶由 prev 变为其他元素时，让一个 Take 按新的堆顶重新等待，调用方需持有锁
+func (q *DelayQueue) changed(prev *delayItem) {
+	if q.head() == prev {
+		return
+	}
+	if q.leader {
+		q.reset.notify()
+	} else {
+		q.available.notify()
+	}
+}
+
+// Put 添加一个在 at 时刻到期的元素
+func (q *DelayQueue) Put(val Delayed, at time.Time) error {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	if q.closed {
+		return ErrClosed
+	}
+	if q.heap.Contains(val.Key()) {
+		return ErrDuplicateKey
+	}
+	prev := q.head()
+	q.heap.Insert(q.newItem(val, at))
+	q.changed(prev)
+	return nil
+}
+
+// Reschedule 修改元素的到期时间，key 不存在时返回 ErrNotFound
+func (q *DelayQueue) Reschedule(key any, at time.Time) error {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	prev := q.head()
+	val := q.heap.Delete(key)
+	if val == nil {
+		return ErrNotFound
+	}
+	q.heap.Insert(q.newItem(val.(*delayItem).val, at))
+	q.changed(prev)
+	return nil
+}
+
+// Cancel 删除元素，key 不存在时返回 nil
+func (q *DelayQueue) Cancel(key any) Delayed {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+
+	prev := q.head()
+	val := q.heap.Delete(key)
+	if val == nil {
+		return nil
+	}
+	q.changed(prev)
+	return val.(*delayItem).val
+}
+
+// Take 取出最早到期的元素，没有到期元素时阻塞
+//	同一时刻只有一个 Take（leader）用定时器等待堆顶到期，其余 Take 等待成为 leader。
+//	队列关闭后仍可取出已到期的元素，没有到期元素时返回 ErrClosed；ctx 被取消时返回 ctx.Err()
+func (q *DelayQueue) Take(ctx context.Context) (Delayed, error) {
+	for {
+		q.mux.Lock()
+		item := q.head()
+		var d time.Duration
+		if item != nil {
+			if d = item.at.Sub(q.clock.Now()); d <= 0 {
+				q.heap.Pop()
+				q.changed(item)
+				q.mux.Unlock()
+				return item.val, nil
+			}
+		}
+		if q.closed {
+			q.mux.Unlock()
+			return nil, ErrClosed
+		}
+
+		var (
+			timer <-chan time.Time
+			stop  func() bool
+		)
+		wake := q.available
+		if item != nil && !q.leader {
+			q.leader = true
+			// 丢弃上一个 leader 没有取走的通知
+			select {
+			case <-q.reset:
+			default:
+			}
+			timer, stop = q.clock.NewTimer(d)
+			wake = q.reset
+		}
+		q.mux.Unlock()
+
+		var err error
+		select {
+		case <-wake:
+		case <-timer:
+		case <-q.done:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+		if stop != nil {
+			// 因其他原因被唤醒时停止定时器，避免定时器在到期前一直堆积
+			stop()
+			q.mux.Lock()
+			q.leader = false
+			if err != nil && q.heap.Size() > 0 {
+				q.available.notify()
+			}
+			q.mux.Unlock()
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// Size 元素个数
+func (q *DelayQueue) Size() int {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	return q.heap.Size()
+}
+
+// Close 关闭队列并唤醒所有等待者
+func (q *DelayQueue) Close() {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	if q.closed {
+		return
+	}
+	q.closed = true
+	close(q.done)
+}
@@ -0,0 +1,270 @@
+package heap
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock 手动推进的时钟
+type fakeClock struct {
+	now     time.Time
+	waiters []*fakeWaiter
+	mux     sync.Mutex
+}
+
+type fakeWaiter struct {
+	at time.Time
+	ch chan time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) (<-chan time.Time, func() bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	w := &fakeWaiter{at: c.now.Add(d), ch: make(chan time.Time, 1)}
+	c.waiters = append(c.waiters, w)
+	return w.ch, func() bool {
+		c.mux.Lock()
+		defer c.mux.Unlock()
+		for i, p := range c.waiters {
+			if p == w {
+				c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Advance 推进时间并触发到期的等待者
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	c.now = c.now.Add(d)
+	var waiters []*fakeWaiter
+	for _, w := range c.waiters {
+		if w.at.After(c.now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.ch <- c.now
+	}
+	c.waiters = waiters
+}
+
+// pending 未触发且未停止的定时器个数
+func (c *fakeClock) pending() int {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	return len(c.waiters)
+}
+
+// blocked 等待直到有 n 个等待者
+func (c *fakeClock) blocked(n int) {
+	for {
+		c.mux.Lock()
+		count := len(c.waiters)
+		c.mux.Unlock()
+		if count >= n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+type job string
+
+func (j job) Key() any { return string(j) }
+
+func TestDelayQueueTake(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue(clock)
+	now := clock.Now()
+	q.Put(job("c"), now.Add(3*time.Second))
+	q.Put(job("a"), now.Add(1*time.Second))
+	q.Put(job("b"), now.Add(2*time.Second))
+	if err := q.Put(job("a"), now); err != ErrDuplicateKey {
+		t.Fatalf("expected ErrDuplicateKey, got %v", err)
+	}
+
+	result := make(chan Delayed)
+	go func() {
+		for i := 0; i < 3; i++ {
+			val, err := q.Take(context.Background())
+			if err != nil {
+				t.Error(err)
+			}
+			result <- val
+		}
+	}()
+
+	for _, want := range []job{"a", "b", "c"} {
+		clock.blocked(1)
+		select {
+		case val := <-result:
+			t.Fatalf("%v should not be ready", val)
+		default:
+		}
+		clock.Advance(time.Second)
+		if val := <-result; val != want {
+			t.Fatalf("%v --> %v", want, val)
+		}
+	}
+}
+
+func TestDelayQueueRescheduleCancel(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue(clock)
+	now := clock.Now()
+	q.Put(job("a"), now.Add(time.Second))
+	q.Put(job("b"), now.Add(2*time.Second))
+	q.Put(job("c"), now.Add(3*time.Second))
+
+	if err := q.Reschedule("c", now); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Reschedule("x", now); err != ErrNotFound {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if val := q.Cancel("a"); val != job("a") {
+		t.Fatalf("cancel: %v", val)
+	}
+	if q.Cancel("a") != nil {
+		t.Fatal("a should be cancelled")
+	}
+
+	if val, _ := q.Take(context.Background()); val != job("c") {
+		t.Fatalf("take: %v", val)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		clock.blocked(1)
+		cancel()
+	}()
+	if _, err := q.Take(ctx); err != context.Canceled {
+		t.Fatalf("expected Canceled, got %v", err)
+	}
+
+	clock.Advance(2 * time.Second)
+	if val, _ := q.Take(context.Background()); val != job("b") {
+		t.Fatalf("take: %v", val)
+	}
+	if q.Size() != 0 {
+		t.Fatalf("size: %d", q.Size())
+	}
+}
+
+func TestDelayQueueClose(t *testing.T) {
+	q := NewDelayQueue(nil)
+	q.Put(job("a"), time.Now().Add(time.Hour))
+
+	done := make(chan error)
+	go func() {
+		_, err := q.Take(context.Background())
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	q.Close()
+	if err := <-done; err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+
+	// 关闭后仍可取出已到期的元素
+	clock := newFakeClock()
+	q = NewDelayQueue(clock)
+	q.Put(job("a"), clock.Now().Add(time.Hour))
+	q.Put(job("b"), clock.Now().Add(time.Second))
+	q.Put(job("c"), clock.Now())
+	clock.Advance(time.Second)
+	q.Close()
+	if err := q.Put(job("d"), clock.Now()); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	for _, want := range []job{"c", "b"} {
+		if val, err := q.Take(context.Background()); err != nil || val != want {
+			t.Fatalf("take: %v, %v", val, err)
+		}
+	}
+	if _, err := q.Take(context.Background()); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if n := clock.pending(); n != 0 {
+		t.Fatalf("pending timers: %d", n)
+	}
+}
+
+// 被 Put/Cancel 唤醒后旧的定时器应被停止，不能随唤醒次数堆积
+func TestDelayQueueStopsTimers(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue(clock)
+	q.Put(job("a"), clock.Now().Add(time.Hour))
+
+	done := make(chan Delayed)
+	go func() {
+		val, _ := q.Take(context.Background())
+		done <- val
+	}()
+	clock.blocked(1)
+
+	for i := 0; i < 100; i++ {
+		q.Put(job("b"), clock.Now().Add(2*time.Hour))
+		q.Cancel("b")
+	}
+	time.Sleep(10 * time.Millisecond)
+	if n := clock.pending(); n != 1 {
+		t.Fatalf("pending timers: %d", n)
+	}
+
+	clock.Advance(time.Hour)
+	if val := <-done; val != job("a") {
+		t.Fatalf("take: %v", val)
+	}
+	if n := clock.pending(); n != 0 {
+		t.Fatalf("pending timers: %d", n)
+	}
+}
+
+// 多个 Take 同时等待时只有 leader 持有定时器
+func TestDelayQueueLeader(t *testing.T) {
+	clock := newFakeClock()
+	q := NewDelayQueue(clock)
+	now := clock.Now()
+
+	result := make(chan Delayed)
+	for i := 0; i < 3; i++ {
+		go func() {
+			val, err := q.Take(context.Background())
+			if err != nil {
+				t.Error(err)
+			}
+			result <- val
+		}()
+	}
+	q.Put(job("b"), now.Add(2*time.Second))
+	q.Put(job("a"), now.Add(time.Second))
+	q.Put(job("c"), now.Add(3*time.Second))
+
+	for _, want := range []job{"a", "b", "c"} {
+		clock.blocked(1)
+		time.Sleep(10 * time.Millisecond)
+		if n := clock.pending(); n != 1 {
+			t.Fatalf("pending timers: %d", n)
+		}
+		clock.Advance(time.Second)
+		if val := <-result; val != want {
+			t.Fatalf("%v --> %v", want, val)
+		}
+	}
+}
@@ -1,16 +1,17 @@
 package heap
 
 import (
-	"container/list"
 	"errors"
 	"math"
+	"sort"
 	"sync"
+	"unsafe"
 )
 
 type node struct {
-	self     *list.Element
-	parent   *node
-	children *list.List
+	left, right *node // 兄弟节点，组成循环双向链表
+	parent      *node
+	child       *node // 任意一个孩子节点
 
 	degree uint
 	marked bool
@@ -18,14 +19,54 @@ type node struct {
 	value Interface
 	key   interface{}
 	v     float64
+
+	seq  int64 // 加入堆的序号，key 重复时用于找出最早加入的节点
+	slot int   // 在 index[key] 中的下标
+}
+
+func newNode(val Interface) *node {
+	n := &node{
+		value: val,
+		key:   val.Key(),
+		v:     val.Value(),
+	}
+	n.left, n.right = n, n
+	return n
+}
+
+// splice 将循环链表 b 拼接到循环链表 a 之后
+func splice(a, b *node) {
+	aRight, bLeft := a.right, b.left
+	a.right, b.left = b, a
+	bLeft.right, aRight.left = aRight, bLeft
+}
+
+// unlink 将 n 从所在的循环链表中摘除
+func unlink(n *node) {
+	n.left.right = n.right
+	n.right.left = n.left
+	n.left, n.right = n, n
 }
 
+// siblings 返回 n 所在循环链表中的全部节点
+func siblings(n *node) []*node {
+	if n == nil {
+		return nil
+	}
+	nodes := []*node{n}
+	for p := n.right; p != n; p = p.right {
+		nodes = append(nodes, p)
+	}
+	return nodes
+}
+
+// FibHeap .
+//	允许多个元素拥有相同的 key，此时 Update/Delete 作用于其中最早加入的一个
 type FibHeap struct {
-	main        *node
-	root        *list.List
-	num         uint
-	index       map[interface{}]*node
-	degreeTable map[uint]*list.Element
+	main   *node // 堆顶，同时也是根链表的入口
+	num    uint
+	index  map[interface{}][]*node
+	lo, hi int64 // 节点序号的范围 [lo, hi)
 
 	t       T
 	mux     sync.RWMutex
@@ -35,11 +76,8 @@ type FibHeap struct {
 // NewFibHeap 初始化 fibonacci heap
 func NewFibHeap(t T) *FibHeap {
 	heap := &FibHeap{
-		index:       make(map[interface{}]*node),
-		degreeTable: make(map[uint]*list.Element),
-
-		root: list.New(),
-		t:    t,
+		index: make(map[interface{}][]*node),
+		t:     t,
 	}
 	if t == MinHeap {
 		heap.compare = func(a, b float64) bool {
@@ -63,35 +101,63 @@ func (h *FibHeap) T() T {
 //	元素的排序指标范围为 (-inf, +inf)
 func (h *FibHeap) Insert(val Interface) error {
 	if math.IsInf(val.Value(), 0) {
-		return errors.New("infinity is for internal use only")
+		return ErrInfinity
 	}
 
 	h.mux.Lock()
-	if _, ok := h.index[val.Key()]; ok {
-		h.mux.Unlock()
-		return errors.New("duplicate key is not allowed")
-	}
-
 	h.insertValue(val)
 	h.mux.Unlock()
 	return nil
 }
 
 func (h *FibHeap) insertValue(val Interface) {
-	n := &node{
-		value: val,
-		key:   val.Key(),
-		v:     val.Value(),
+	n := newNode(val)
+	n.seq = h.hi
+	h.hi++
+	h.addIndex(n)
+	h.num++
+	h.addRoot(n)
+}
+
+func (h *FibHeap) addIndex(n *node) {
+	n.slot = len(h.index[n.key])
+	h.index[n.key] = append(h.index[n.key], n)
+}
+
+func (h *FibHeap) removeIndex(n *node) {
+	nodes := h.index[n.key]
+	last := len(nodes) - 1
+	nodes[n.slot] = nodes[last]
+	nodes[n.slot].slot = n.slot
+	nodes[last] = nil
+	if last == 0 {
+		delete(h.index, n.key)
+		return
 	}
-	n.children = list.New()
-	n.self = h.root.PushBack(n)
+	h.index[n.key] = nodes[:last]
+}
 
-	h.index[val.Key()] = n
-	h.num++
-	if h.main == nil || h.compare(n.v, h.main.v) {
+// earliestNode 返回 key 相同的节点中最早加入的一个
+func earliestNode(nodes []*node) *node {
+	n := nodes[0]
+	for _, p := range nodes[1:] {
+		if p.seq < n.seq {
+			n = p
+		}
+	}
+	return n
+}
+
+// addRoot 将循环链表 n 加入根链表
+func (h *FibHeap) addRoot(n *node) {
+	if h.main == nil {
 		h.main = n
 		return
 	}
+	splice(h.main, n)
+	if h.compare(n.v, h.main.v) {
+		h.main = n
+	}
 }
 
 // Pop 返回并移除堆顶元素
@@ -105,24 +171,26 @@ func (h *FibHeap) Pop() (val Interface, err error) {
 	h.mux.Unlock()
 	return node.value, nil
 }
+
 func (h *FibHeap) popNode() *node {
 	top := h.main
-	h.root.Remove(top.self)
-	delete(h.degreeTable, top.degree)
-	delete(h.index, top.key)
+	h.removeIndex(top)
 	h.num--
 
-	if h.num == 0 {
+	if top.child != nil {
+		for _, child := range siblings(top.child) {
+			child.parent = nil
+		}
+		splice(top, top.child)
+		top.child = nil
+	}
+
+	next := top.right
+	unlink(top)
+	if next == top {
 		h.main = nil
 	} else {
-		children := top.children
-		if children != nil {
-			for e := children.Front(); e != nil; e = e.Next() {
-				child := e.Value.(*node)
-				child.parent = nil
-				child.self = h.root.PushBack(child)
-			}
-		}
+		h.main = next
 		h.consolidate()
 	}
 
@@ -130,49 +198,43 @@ func (h *FibHeap) popNode() *node {
 }
 
 func (h *FibHeap) consolidate() {
-	var main *node
-	if v := h.root.Front(); v != nil {
-		main = v.Value.(*node)
-	}
-	for tree := h.root.Front(); tree != nil; {
-		treeNode := tree.Value.(*node)
-		if h.compare(treeNode.v, main.v) {
-			main = treeNode
-		}
-
-		if otherTree, ok := h.degreeTable[tree.Value.(*node).degree]; !ok {
-			h.degreeTable[treeNode.degree] = tree
-			tree = tree.Next()
-		} else {
-			if tree == otherTree {
-				tree = tree.Next()
-				continue
+	degreeTable := make(map[uint]*node)
+	roots := siblings(h.main)
+	for _, tree := range roots {
+		for {
+			other, ok := degreeTable[tree.degree]
+			if !ok {
+				break
 			}
-
-			otherTreeNode := otherTree.Value.(*node)
-			delete(h.degreeTable, otherTreeNode.degree)
-
-			if h.compare(treeNode.v, otherTreeNode.v) {
-				h.root.Remove(otherTree)
-				h.merge(treeNode, otherTreeNode)
-				h.degreeTable[treeNode.degree] = tree
-				tree = tree.Next()
-			} else {
-				nextTree := tree.Next()
-				h.root.Remove(tree)
-				h.merge(otherTreeNode, treeNode)
-				h.degreeTable[otherTreeNode.degree] = otherTree
-				tree = nextTree
+			delete(degreeTable, tree.degree)
+			if !h.compare(tree.v, other.v) {
+				tree, other = other, tree
 			}
+			h.merge(tree, other)
+		}
+		degreeTable[tree.degree] = tree
+	}
+
+	// 按根链表顺序选出堆顶，保证相同指标的元素出堆顺序是确定的
+	var main *node
+	for _, tree := range roots {
+		if tree.parent == nil && (main == nil || h.compare(tree.v, main.v)) {
+			main = tree
 		}
 	}
 	h.main = main
 }
 
+// merge 将根节点 child 链接为 parent 的孩子
 func (h *FibHeap) merge(parent, child *node) {
+	unlink(child)
 	child.marked = false
-	child.self = parent.children.PushBack(child)
 	child.parent = parent
+	if parent.child == nil {
+		parent.child = child
+	} else {
+		splice(parent.child, child)
+	}
 	parent.degree++
 }
 
@@ -192,12 +254,22 @@ func (h *FibHeap) Peek() (val Interface, err error) {
 
 // UpdateValue 根据元素的 key 更新其值
 func (h *FibHeap) UpdateValue(val Interface) {
+	h.Update(val)
+}
+
+// Update 根据元素的 key 更新其值，key 不存在时返回 ErrNotFound
+func (h *FibHeap) Update(val Interface) error {
+	if math.IsInf(val.Value(), 0) {
+		return ErrInfinity
+	}
+
 	h.mux.Lock()
-	p, ok := h.index[val.Key()]
+	nodes, ok := h.index[val.Key()]
 	if !ok {
 		h.mux.Unlock()
-		return
+		return ErrNotFound
 	}
+	p := earliestNode(nodes)
 
 	p.v = val.Value()
 	if val.Value() < p.value.Value() {
@@ -207,6 +279,7 @@ func (h *FibHeap) UpdateValue(val Interface) {
 	}
 	p.value = val
 	h.mux.Unlock()
+	return nil
 }
 
 func (h *FibHeap) decreaseValue(p *node) {
@@ -261,7 +334,6 @@ func (h *FibHeap) increaseValue(p *node) {
 		h.cut(p)
 		if h.compare(p.v, h.main.v) {
 			h.main = p
-			return
 		}
 		h.cascadingCut(parent)
 	}
@@ -275,13 +347,20 @@ func (h *FibHeap) cut(p *node) {
 
 	parent := p.parent
 	parent.degree--
-	parent.children.Remove(p.self)
+	if p.right == p {
+		parent.child = nil
+	} else {
+		if parent.child == p {
+			parent.child = p.right
+		}
+		unlink(p)
+	}
 	p.parent = nil
-	p.self = h.root.PushBack(p)
+	splice(h.main, p)
 }
 
 func (h *FibHeap) cascadingCut(parent *node) {
-	if parent == nil {
+	if parent == nil || parent.parent == nil {
 		return
 	}
 
@@ -290,36 +369,28 @@ func (h *FibHeap) cascadingCut(parent *node) {
 		return
 	}
 
+	grandparent := parent.parent
 	h.cut(parent)
-	h.cascadingCut(parent.parent)
+	h.cascadingCut(grandparent)
 }
 
 func (h *FibHeap) moveChildren2Root(p *node) {
-	children := p.children
-	if children == nil {
+	if p.child == nil {
 		return
 	}
 
-	for e := children.Front(); e != nil; {
-		child := e.Value.(*node)
+	for _, child := range siblings(p.child) {
 		child.parent = nil
-		child.self = h.root.PushBack(child)
-		next := e.Next()
-		children.Remove(e)
-		e = next
+		child.marked = false
 	}
+	splice(h.main, p.child)
+	p.child = nil
 	p.degree = 0
 }
 
 func (h *FibHeap) findMain() {
-	start := h.root.Front()
-	if start == nil {
-		return
-	}
-
-	main := start.Value.(*node)
-	for tree := start.Next(); tree != nil; tree = tree.Next() {
-		p := tree.Value.(*node)
+	main := h.main
+	for _, p := range siblings(h.main) {
 		if h.compare(p.v, main.v) {
 			main = p
 		}
@@ -327,30 +398,243 @@ func (h *FibHeap) findMain() {
 	h.main = main
 }
 
-// Union 合并另一个堆
+// Union 合并另一个堆，合并后 target 被清空
+//	根链表直接拼接，时间复杂度 O(1)；key 索引由较小的一方并入较大的一方，
+//	时间复杂度 O(min(n, m))，并非均摊 O(1)：合并后仍要按 key 查找，
+//	各自独立的哈希索引无法在常数时间内合并。一个节点所在的索引被并入时规模至少翻倍，
+//	因此每个节点最多被移动 O(log n) 次。target 中的元素视为按原先的顺序依次加入 h
 func (h *FibHeap) Union(target *FibHeap) error {
-	h.mux.Lock()
+	if target == h {
+		return nil
+	}
+	if target.t != h.t {
+		return errors.New("heap type mismatch")
+	}
+
+	unlock := lockPair(h, target)
+	defer unlock()
+
+	// 只重新编号较小一方的节点，使 h 的节点序号都小于 target 的节点序号
+	small, large := target.index, h.index
+	if len(small) > len(large) {
+		small, large = large, small
+		for _, nodes := range small {
+			for _, n := range nodes {
+				n.seq += target.lo - h.hi
+			}
+		}
+		h.lo, h.hi = h.lo+target.lo-h.hi, target.hi
+	} else {
+		for _, nodes := range small {
+			for _, n := range nodes {
+				n.seq += h.hi - target.lo
+			}
+		}
+		h.hi += target.hi - target.lo
+	}
+	for k, nodes := range small {
+		for _, n := range nodes {
+			n.slot = len(large[k])
+			large[k] = append(large[k], n)
+		}
+	}
+	h.index = large
+
+	if target.main != nil {
+		h.addRoot(target.main)
+	}
+	h.num += target.num
+
+	target.main = nil
+	target.num = 0
+	target.index = make(map[interface{}][]*node)
+	target.lo, target.hi = 0, 0
+	return nil
+}
+
+// lockPair 按地址顺序对两个堆加锁，避免 a.Union(b) 与 b.Union(a) 并发时死锁
+func lockPair(a, b *FibHeap) (unlock func()) {
+	if uintptr(unsafe.Pointer(a)) > uintptr(unsafe.Pointer(b)) {
+		a, b = b, a
+	}
+	a.mux.Lock()
+	b.mux.Lock()
+	return func() {
+		b.mux.Unlock()
+		a.mux.Unlock()
+	}
+}
+
+// Meld 合并另一个堆的副本，target 保持不变
+func (h *FibHeap) Meld(target *FibHeap) error {
+	return h.Union(target.Clone())
+}
+
+// Clone 复制一个结构完全相同的堆
+func (h *FibHeap) Clone() *FibHeap {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	heap := NewFibHeap(h.t)
+	heap.num = h.num
+	heap.lo, heap.hi = h.lo, h.hi
+	if h.main != nil {
+		heap.main = heap.cloneList(h.main, nil)
+	}
+	return heap
+}
 
-	for k := range target.index {
-		if _, exists := h.index[k]; exists {
-			h.mux.Unlock()
-			return errors.New("duplicate tag is found in the target heap")
+// cloneList 复制以 n 为入口的循环链表及其子树，返回 n 的副本
+func (h *FibHeap) cloneList(n, parent *node) *node {
+	var first, last *node
+	for _, p := range siblings(n) {
+		c := &node{
+			parent: parent,
+			degree: p.degree,
+			marked: p.marked,
+			value:  p.value,
+			key:    p.key,
+			v:      p.v,
+			seq:    p.seq,
+		}
+		c.left, c.right = c, c
+		h.addIndex(c)
+		if p.child != nil {
+			c.child = h.cloneList(p.child, c)
+		}
+		if first == nil {
+			first = c
+		} else {
+			splice(last, c)
 		}
+		last = c
+	}
+	return first
+}
+
+// Merge 将 other 中的元素全部移入 h，other 会被清空
+//	other 中存在排序指标为 ±inf 的元素时返回 ErrInfinity，两个队列均不变
+func (h *FibHeap) Merge(other PriorityQueue) error {
+	if other == PriorityQueue(h) {
+		return nil
+	}
+	if target, ok := other.(*FibHeap); ok && target.t == h.t {
+		return h.Union(target)
 	}
 
-	for _, node := range target.index {
-		h.insertValue(node.value)
+	// 先检查再取出，避免移动一部分元素后失败
+	var err error
+	other.Range(func(val Interface) bool {
+		if math.IsInf(val.Value(), 0) {
+			err = ErrInfinity
+		}
+		return err == nil
+	})
+	if err != nil {
+		return err
 	}
 
+	values := drain(other)
+	h.mux.Lock()
+	for _, val := range values {
+		h.insertValue(val)
+	}
 	h.mux.Unlock()
 	return nil
 }
 
+// Contains 堆中是否存在 key
+func (h *FibHeap) Contains(key any) bool {
+	h.mux.RLock()
+	_, ok := h.index[key]
+	h.mux.RUnlock()
+	return ok
+}
+
+// Size 元素个数
+func (h *FibHeap) Size() int {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	return int(h.num)
+}
+
+// Range 以任意顺序对每个元素调用 f，f 返回 false 时停止
+//	f 中不能修改堆
+func (h *FibHeap) Range(f func(val Interface) bool) {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	for _, nodes := range h.index {
+		for _, n := range nodes {
+			if !f(n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Drain 按出堆顺序取出全部元素，堆被清空
+func (h *FibHeap) Drain() []Interface {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	values := make([]Interface, 0, h.num)
+	for h.main != nil {
+		values = append(values, h.popNode().value)
+	}
+	return values
+}
+
+// take 按加入顺序取出全部元素并清空堆
+func (h *FibHeap) take() []Interface {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	nodes := make([]*node, 0, h.num)
+	for _, ns := range h.index {
+		nodes = append(nodes, ns...)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].seq < nodes[j].seq
+	})
+	values := make([]Interface, len(nodes))
+	for i, n := range nodes {
+		values[i] = n.value
+	}
+	h.main, h.num = nil, 0
+	h.index = make(map[interface{}][]*node)
+	h.lo, h.hi = 0, 0
+	return values
+}
+
+// Sorted 按出堆顺序返回全部元素，不修改堆
+//	持锁期间复制元素，排序时不读取可能被 Update 修改的字段
+func (h *FibHeap) Sorted() []Interface {
+	h.mux.RLock()
+	nodes := make([]entry, 0, h.num)
+	for _, ns := range h.index {
+		for _, n := range ns {
+			nodes = append(nodes, entry{value: n.value, v: n.v})
+		}
+	}
+	h.mux.RUnlock()
+
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].v != nodes[j].v && h.compare(nodes[i].v, nodes[j].v)
+	})
+	values := make([]Interface, len(nodes))
+	for i, n := range nodes {
+		values[i] = n.value
+	}
+	return values
+}
+
 // Delete 删除元素
 // 	先将元素的排序指标更新为无穷大/无穷小，然后 pop 出堆顶元素
 func (h *FibHeap) Delete(key interface{}) Interface {
 	h.mux.Lock()
-	if node, exists := h.index[key]; exists {
+	if nodes, exists := h.index[key]; exists {
+		node := earliestNode(nodes)
 		h.deleteNode(node)
 		h.mux.Unlock()
 		return node.value
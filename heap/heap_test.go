@@ -2,7 +2,9 @@ package heap
 
 import (
 	"log"
+	"math"
 	"math/rand"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -382,3 +384,415 @@ func TestFibHeapDelete(t *testing.T) {
 		t.Log(val.(*User))
 	}
 }
+
+func TestPriorityQueue(t *testing.T) {
+	for _, pq := range []PriorityQueue{NewBinaryHeap(MinHeap), NewFibHeap(MinHeap)} {
+		for i, age := range []int{20, 25, 22, 26, 19, 30} {
+			if err := pq.Insert(&User{ID: i + 1, Age: age}); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if err := pq.Update(&User{ID: 6, Age: 10}); err != nil {
+			t.Fatal(err)
+		}
+		if err := pq.Update(&User{ID: 100, Age: 10}); err != ErrNotFound {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+		if v := pq.Delete(1); v == nil || v.Value() != 20 {
+			t.Fatalf("delete: %v", v)
+		}
+		if pq.Delete(1) != nil || pq.Contains(1) || !pq.Contains(2) {
+			t.Fatal("key 1 should be deleted")
+		}
+
+		result := []float64{10, 19, 22, 25, 26}
+		if pq.Size() != len(result) {
+			t.Fatalf("size: %d", pq.Size())
+		}
+		for _, want := range result {
+			val, err := pq.Pop()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if val.Value() != want {
+				t.Fatalf("%f --> %f", want, val.Value())
+			}
+		}
+	}
+}
+
+// 重复 key：Update/Delete 作用于最早插入的元素
+func TestPriorityQueueDuplicateKey(t *testing.T) {
+	for _, pq := range []PriorityQueue{NewBinaryHeap(MinHeap), NewFibHeap(MinHeap)} {
+		pq.Insert(&User{ID: 1, Name: "a", Age: 20})
+		if err := pq.Insert(&User{ID: 1, Name: "b", Age: 5}); err != nil || pq.Size() != 2 {
+			t.Fatalf("%T: err %v, size %d", pq, err, pq.Size())
+		}
+
+		pq.Insert(&User{ID: 1, Name: "c", Age: 7})
+		if v := pq.Delete(1); v == nil || v.(*User).Name != "a" {
+			t.Fatalf("delete should remove the earliest, got %v", v)
+		}
+		if err := pq.Update(&User{ID: 1, Name: "b", Age: 30}); err != nil {
+			t.Fatal(err)
+		}
+		if v, _ := pq.Peek(); v.(*User).Name != "c" {
+			t.Fatalf("update should change the earliest, peek %v", v)
+		}
+		if v := pq.Delete(1); v == nil || v.(*User).Name != "b" || !pq.Contains(1) {
+			t.Fatalf("delete: %v", v)
+		}
+		if v := pq.Delete(1); v == nil || v.(*User).Name != "c" || pq.Contains(1) {
+			t.Fatalf("delete: %v", v)
+		}
+	}
+}
+
+func TestPriorityQueueMerge(t *testing.T) {
+	for _, pq := range []PriorityQueue{NewBinaryHeap(MaxHeap), NewFibHeap(MaxHeap)} {
+		other := NewBinaryHeap(MaxHeap)
+		for i, v := range []float64{3, 9, 1} {
+			pq.Insert(&TValue{key: int64(i), val: v})
+			other.Insert(&TValue{key: int64(i + 10), val: v + 1})
+		}
+
+		if err := pq.Merge(other); err != nil {
+			t.Fatal(err)
+		}
+		if other.Size() != 0 || pq.Size() != 6 {
+			t.Fatalf("size: %d, %d", pq.Size(), other.Size())
+		}
+
+		result := []float64{10, 9, 4, 3, 2, 1}
+		for _, want := range result {
+			val, _ := pq.Pop()
+			if val.Value() != want {
+				t.Fatalf("%f --> %f", want, val.Value())
+			}
+		}
+	}
+
+	// 移入的元素排在已有的同 key 元素之后
+	for _, pq := range []PriorityQueue{NewBinaryHeap(MinHeap), NewFibHeap(MinHeap)} {
+		other := NewBinaryHeap(MinHeap)
+		pq.Insert(&TValue{key: 1, val: 1})
+		other.Insert(&TValue{key: 1, val: 2})
+		other.Insert(&TValue{key: 1, val: 3})
+		if err := pq.Merge(other); err != nil {
+			t.Fatal(err)
+		}
+		for _, want := range []float64{1, 2, 3} {
+			if v := pq.Delete(int64(1)); v == nil || v.Value() != want {
+				t.Fatalf("%T: delete %v, want %f", pq, v, want)
+			}
+		}
+	}
+	// 合并失败时两个队列均不变
+	fh := NewFibHeap(MinHeap)
+	fh.Insert(&TValue{key: 1, val: 1})
+	other := NewBinaryHeap(MinHeap)
+	other.Insert(&TValue{key: 2, val: 2})
+	other.Insert(&TValue{key: 3, val: math.Inf(1)})
+	if err := fh.Merge(other); err != ErrInfinity {
+		t.Fatalf("expected ErrInfinity, got %v", err)
+	}
+	if fh.Size() != 1 || other.Size() != 2 {
+		t.Fatalf("size: %d, %d", fh.Size(), other.Size())
+	}
+}
+
+func TestFibHeapUnion(t *testing.T) {
+	heap := NewFibHeap(MinHeap)
+	target := NewFibHeap(MinHeap)
+	for i := 0; i < 50; i++ {
+		heap.Insert(&TValue{key: int64(i), val: float64(rand.Intn(100))})
+		target.Insert(&TValue{key: int64(i + 50), val: float64(rand.Intn(100))})
+	}
+	// 让两个堆都形成多层的树
+	heap.Pop()
+	target.Pop()
+
+	if err := heap.Union(target); err != nil {
+		t.Fatal(err)
+	}
+	if target.Size() != 0 || heap.Size() != 98 {
+		t.Fatalf("size: %d, %d", heap.Size(), target.Size())
+	}
+	if _, err := target.Pop(); err != ErrEmpty {
+		t.Fatal("target should be empty")
+	}
+
+	last := -1.0
+	for i := 0; i < 98; i++ {
+		val, err := heap.Pop()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if val.Value() < last {
+			t.Fatalf("%f < %f", val.Value(), last)
+		}
+		last = val.Value()
+	}
+}
+
+func TestFibHeapUnionDuplicate(t *testing.T) {
+	// 分别让 heap 和 target 作为索引较小的一方
+	for _, n := range []int{1, 3} {
+		heap := NewFibHeap(MaxHeap)
+		target := NewFibHeap(MaxHeap)
+		for i := 0; i < n; i++ {
+			heap.Insert(&TValue{key: int64(i + 10), val: 0})
+		}
+		heap.Insert(&TValue{key: 1, val: 1})
+		heap.Insert(&TValue{key: 1, val: 2})
+		target.Insert(&TValue{key: 2, val: 2})
+		target.Insert(&TValue{key: 1, val: 3})
+
+		if err := heap.Union(target); err != nil {
+			t.Fatal(err)
+		}
+		if err := heap.Validate(); err != nil {
+			t.Fatal(err)
+		}
+		if heap.Size() != n+4 || target.Size() != 0 {
+			t.Fatalf("size: %d, %d", heap.Size(), target.Size())
+		}
+		for _, want := range []float64{1, 2, 3} {
+			if v := heap.Delete(int64(1)); v == nil || v.Value() != want {
+				t.Fatalf("delete %v, want %f", v, want)
+			}
+		}
+
+		// 合并后插入的元素排在最后
+		heap.Insert(&TValue{key: 2, val: 4})
+		if v := heap.Delete(int64(2)); v == nil || v.Value() != 2 {
+			t.Fatalf("delete %v, want 2", v)
+		}
+	}
+}
+
+// a.Union(b) 与 b.Union(a) 并发执行时不能死锁
+func TestFibHeapUnionConcurrent(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 2000; i++ {
+			a, b := NewFibHeap(MinHeap), NewFibHeap(MinHeap)
+			a.Insert(&TValue{key: 1, val: 1})
+			b.Insert(&TValue{key: 2, val: 2})
+
+			var wg sync.WaitGroup
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				a.Union(b)
+			}()
+			go func() {
+				defer wg.Done()
+				b.Merge(a)
+			}()
+			wg.Wait()
+			if a.Size()+b.Size() != 2 {
+				t.Errorf("size: %d, %d", a.Size(), b.Size())
+				return
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("deadlock in concurrent Union")
+	}
+}
+
+func TestFibHeapMeld(t *testing.T) {
+	heap := NewFibHeap(MaxHeap)
+	target := NewFibHeap(MaxHeap)
+	for i := 0; i < 10; i++ {
+		heap.Insert(&TValue{key: int64(i), val: float64(i)})
+		target.Insert(&TValue{key: int64(i + 10), val: float64(i + 10)})
+	}
+	target.Pop()
+
+	if err := heap.Meld(target); err != nil {
+		t.Fatal(err)
+	}
+	if heap.Size() != 19 || target.Size() != 9 {
+		t.Fatalf("size: %d, %d", heap.Size(), target.Size())
+	}
+
+	// 修改副本不影响 target
+	heap.Update(&TValue{key: 12, val: 100})
+	if val, _ := target.Peek(); val.Value() != 18 {
+		t.Fatalf("target top: %f", val.Value())
+	}
+	for want := 18.0; want >= 10; want-- {
+		val, _ := target.Pop()
+		if val.Value() != want {
+			t.Fatalf("%f --> %f", want, val.Value())
+		}
+	}
+	if val, _ := heap.Pop(); val.Value() != 100 {
+		t.Fatalf("heap top: %f", val.Value())
+	}
+}
+
+func TestHeapSnapshot(t *testing.T) {
+	testdata := []value{79, 66, 43, 83, 30, 87, 38, 55, 91, 72, 49, 9}
+	result := []float64{9, 30, 38, 43, 49, 55, 66, 72, 79, 83, 87, 91}
+
+	type snapshotHeap interface {
+		PriorityQueue
+		Range(func(Interface) bool)
+		Drain() []Interface
+		Sorted() []Interface
+	}
+	bh, fh := NewBinaryHeap(MinHeap), NewFibHeap(MinHeap)
+	for _, val := range testdata {
+		bh.Insert(val)
+		fh.Insert(val)
+	}
+	fh.Pop()
+	fh.Insert(value(9))
+
+	for _, c := range []struct {
+		heap  snapshotHeap
+		clone snapshotHeap
+	}{{bh, bh.Clone()}, {fh, fh.Clone()}} {
+		var sum float64
+		c.heap.Range(func(val Interface) bool {
+			sum += val.Value()
+			return true
+		})
+		if sum != 702 {
+			t.Fatalf("sum: %f", sum)
+		}
+
+		count := 0
+		c.heap.Range(func(val Interface) bool {
+			count++
+			return count < 3
+		})
+		if count != 3 {
+			t.Fatalf("range should stop early, count: %d", count)
+		}
+
+		for _, values := range [][]Interface{c.heap.Sorted(), c.clone.Drain(), c.heap.Drain()} {
+			if len(values) != len(result) {
+				t.Fatalf("len: %d", len(values))
+			}
+			for i, val := range values {
+				if val.Value() != result[i] {
+					t.Fatalf("%f --> %f", result[i], val.Value())
+				}
+			}
+		}
+		if c.heap.Size() != 0 || c.clone.Size() != 0 {
+			t.Fatal("heap should be drained")
+		}
+	}
+}
+
+func TestHeapClone(t *testing.T) {
+	bh := NewBinaryHeap(MaxHeap)
+	for i := 0; i < 10; i++ {
+		bh.Insert(&TValue{key: int64(i), val: float64(i)})
+	}
+	clone := bh.Clone()
+	clone.Update(&TValue{key: 3, val: 100})
+	clone.Delete(int64(9))
+
+	if val, _ := bh.Peek(); val.Value() != 9 {
+		t.Fatalf("heap top: %f", val.Value())
+	}
+	if val, _ := clone.Pop(); val.Key() != int64(3) {
+		t.Fatalf("clone top: %v", val.Key())
+	}
+	if bh.Size() != 10 || clone.Size() != 8 {
+		t.Fatalf("size: %d, %d", bh.Size(), clone.Size())
+	}
+}
+
+// Sorted 与 Update 并发执行，需配合 -race 检查
+func TestHeapSortedConcurrentUpdate(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	type sortedQueue interface {
+		PriorityQueue
+		Sorted() []Interface
+	}
+
+	for _, pq := range []sortedQueue{NewBinaryHeap(MinHeap), NewFibHeap(MinHeap)} {
+		for i := 0; i < 100; i++ {
+			pq.Insert(&TValue{key: int64(i), val: float64(i)})
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 1000; i++ {
+				pq.Update(&TValue{key: int64(i % 100), val: float64(1000 - i)})
+				runtime.Gosched()
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				if n := len(pq.Sorted()); n != 100 {
+					t.Errorf("sorted: %d elements", n)
+					return
+				}
+				runtime.Gosched()
+			}
+		}()
+		wg.Wait()
+	}
+}
+
+func TestStableBinaryHeap(t *testing.T) {
+	for _, typ := range []T{MinHeap, MaxHeap} {
+		heap := NewStableBinaryHeap(typ)
+		var id int64
+		for round := 0; round < 5; round++ {
+			for _, v := range []float64{1, 2, 3} {
+				id++
+				heap.Insert(&TValue{key: id, val: v})
+			}
+		}
+
+		var last *TValue
+		for heap.Size() > 0 {
+			val, _ := heap.Pop()
+			cur := val.(*TValue)
+			if last != nil && last.val == cur.val && last.key > cur.key {
+				t.Fatalf("FIFO broken: %d before %d", last.key, cur.key)
+			}
+			last = cur
+		}
+	}
+}
+
+func TestStableBinaryHeapMerge(t *testing.T) {
+	heap := NewStableBinaryHeap(MinHeap)
+	other := NewStableBinaryHeap(MinHeap)
+	for i := int64(1); i <= 20; i++ {
+		heap.Insert(&TValue{key: i, val: 0})
+		other.Insert(&TValue{key: i + 20, val: 0})
+	}
+	heap.Merge(other)
+	clone := heap.Clone()
+
+	sorted := heap.Sorted()
+	for i, values := range [][]Interface{sorted, heap.Drain(), clone.Drain()} {
+		for j, val := range values {
+			if val.Key() != int64(j+1) {
+				t.Fatalf("%d: %d --> %v", i, j+1, val.Key())
+			}
+		}
+	}
+}
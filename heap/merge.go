@@ -0,0 +1,114 @@
+package heap
+
+import "context"
+
+// Iterator 有序元素的迭代器
+type Iterator interface {
+	// Next 返回下一个元素，没有更多元素时返回 false
+	Next() (Interface, bool)
+}
+
+type sliceIterator struct {
+	values []Interface
+}
+
+// SliceIterator 按顺序遍历 values 的迭代器
+func SliceIterator(values []Interface) Iterator {
+	return &sliceIterator{values: values}
+}
+
+func (it *sliceIterator) Next() (Interface, bool) {
+	if len(it.values) == 0 {
+		return nil, false
+	}
+	val := it.values[0]
+	it.values = it.values[1:]
+	return val, true
+}
+
+type chanIterator struct {
+	ctx context.Context
+	ch  <-chan Interface
+}
+
+// ChanIterator 从 ch 中读取元素的迭代器，ch 关闭或 ctx 被取消时结束
+func ChanIterator(ctx context.Context, ch <-chan Interface) Iterator {
+	return &chanIterator{ctx: ctx, ch: ch}
+}
+
+func (it *chanIterator) Next() (Interface, bool) {
+	select {
+	case val, ok := <-it.ch:
+		return val, ok
+	case <-it.ctx.Done():
+		return nil, false
+	}
+}
+
+// mergeItem 记录元素来自哪个迭代器
+type mergeItem struct {
+	value Interface
+	src   int
+}
+
+func (m *mergeItem) Key() any { return m.src }
+
+func (m *mergeItem) Value() float64 { return m.value.Value() }
+
+// MergeK 将 K 个有序的迭代器合并为一个有序的流
+//	t 为 MinHeap 时输入需按排序指标升序排列，MaxHeap 时需降序排列；
+//	排序指标相同时按迭代器的顺序输出。
+//	dedup 为 true 时，Key 与排序指标都与已输出元素相同的元素会被丢弃，即保留靠前的迭代器中的元素。
+//	所有迭代器结束或 ctx 被取消时关闭返回的 channel。
+//	合并在单独的 goroutine 中进行，调用方在读完之前停止读取时必须取消 ctx，
+//	否则该 goroutine 会一直阻塞在发送上无法退出
+func MergeK(ctx context.Context, t T, dedup bool, iters ...Iterator) <-chan Interface {
+	out := make(chan Interface)
+	go func() {
+		defer close(out)
+
+		// 排序指标相同时按来源下标出堆
+		less := byValue(t)
+		heap := newPlainHeap(func(a, b *entry) bool {
+			if a.v == b.v {
+				return a.value.(*mergeItem).src < b.value.(*mergeItem).src
+			}
+			return less(a, b)
+		})
+		advance := func(src int) {
+			if val, ok := iters[src].Next(); ok {
+				heap.push(&entry{value: &mergeItem{value: val, src: src}, v: val.Value()})
+			}
+		}
+		for src := range iters {
+			advance(src)
+		}
+
+		// 当前排序指标下已输出的 key
+		var (
+			lastValue float64
+			seen      map[any]struct{}
+		)
+		for heap.len() > 0 {
+			item := heap.pop().value.(*mergeItem)
+			advance(item.src)
+
+			if dedup {
+				if seen == nil || item.Value() != lastValue {
+					lastValue, seen = item.Value(), make(map[any]struct{})
+				}
+				if _, ok := seen[item.value.Key()]; ok {
+					continue
+				}
+				seen[item.value.Key()] = struct{}{}
+			}
+
+			select {
+			case out <- item.value:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
@@ -0,0 +1,130 @@
+package heap
+
+import (
+	"context"
+	"testing"
+)
+
+func collect(ch <-chan Interface) []float64 {
+	var values []float64
+	for val := range ch {
+		values = append(values, val.Value())
+	}
+	return values
+}
+
+func toIterator(values ...float64) Iterator {
+	l := make([]Interface, len(values))
+	for i, v := range values {
+		l[i] = value(v)
+	}
+	return SliceIterator(l)
+}
+
+func TestMergeK(t *testing.T) {
+	ctx := context.Background()
+	got := collect(MergeK(ctx, MinHeap, false,
+		toIterator(1, 4, 7, 10),
+		toIterator(2, 5, 8),
+		toIterator(),
+		toIterator(3, 4, 9, 11, 12),
+	))
+	want := []float64{1, 2, 3, 4, 4, 5, 7, 8, 9, 10, 11, 12}
+	if len(got) != len(want) {
+		t.Fatalf("%v --> %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%v --> %v", want, got)
+		}
+	}
+
+	got = collect(MergeK(ctx, MaxHeap, true,
+		toIterator(9, 7, 7, 3),
+		toIterator(8, 7, 3, 1),
+	))
+	want = []float64{9, 8, 7, 3, 1}
+	if len(got) != len(want) {
+		t.Fatalf("%v --> %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("%v --> %v", want, got)
+		}
+	}
+}
+
+func TestMergeKDedupByKey(t *testing.T) {
+	a := []Interface{&User{ID: 1, Age: 20}, &User{ID: 2, Age: 20}, &User{ID: 3, Age: 30}}
+	b := []Interface{&User{ID: 2, Age: 20}, &User{ID: 4, Age: 20}, &User{ID: 3, Age: 31}}
+
+	var ids []int
+	for val := range MergeK(context.Background(), MinHeap, true, SliceIterator(a), SliceIterator(b)) {
+		ids = append(ids, val.(*User).ID)
+	}
+	want := []int{1, 2, 4, 3, 3}
+	if len(ids) != len(want) {
+		t.Fatalf("%v --> %v", want, ids)
+	}
+	for i := range want {
+		if ids[i] != want[i] {
+			t.Fatalf("%v --> %v", want, ids)
+		}
+	}
+}
+
+// 排序指标相同时按迭代器的顺序输出，与元素进入堆的先后无关
+func TestMergeKTieOrder(t *testing.T) {
+	a := []Interface{&User{ID: 1, Name: "a1", Age: 1}, &User{ID: 2, Name: "a5", Age: 5}}
+	b := []Interface{&User{ID: 2, Name: "b5", Age: 5}}
+
+	for _, dedup := range []bool{false, true} {
+		var names []string
+		for val := range MergeK(context.Background(), MinHeap, dedup, SliceIterator(a), SliceIterator(b)) {
+			names = append(names, val.(*User).Name)
+		}
+		want := []string{"a1", "a5", "b5"}
+		if dedup {
+			want = want[:2]
+		}
+		if len(names) != len(want) {
+			t.Fatalf("dedup %v: %v --> %v", dedup, want, names)
+		}
+		for i := range want {
+			if names[i] != want[i] {
+				t.Fatalf("dedup %v: %v --> %v", dedup, want, names)
+			}
+		}
+	}
+}
+
+func TestMergeKChan(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chans := make([]Iterator, 3)
+	for i := range chans {
+		ch := make(chan Interface)
+		go func(base int) {
+			defer close(ch)
+			for v := base; ; v += 3 {
+				select {
+				case ch <- value(v):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}(i)
+		chans[i] = ChanIterator(ctx, ch)
+	}
+
+	out := MergeK(ctx, MinHeap, false, chans...)
+	for want := 0.0; want < 100; want++ {
+		if val := <-out; val.Value() != want {
+			t.Fatalf("%f --> %f", want, val.Value())
+		}
+	}
+	cancel()
+	for range out {
+	}
+}
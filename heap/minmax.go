@@ -0,0 +1,196 @@
+package heap
+
+import (
+	"math/bits"
+	"sync"
+)
+
+// MinMaxHeap 最小最大堆（双端优先队列）
+//	偶数层（根为第 0 层）的节点不大于其子孙节点，奇数层的节点不小于其子孙节点，
+//	因此最小值位于根节点，最大值位于第 1 层
+type MinMaxHeap struct {
+	heap []Interface
+
+	mux sync.RWMutex
+}
+
+// NewMinMaxHeap 创建最小最大堆
+func NewMinMaxHeap() *MinMaxHeap {
+	return &MinMaxHeap{
+		heap: make([]Interface, 0),
+	}
+}
+
+// isMinLevel index 是否位于最小层
+func isMinLevel(index int) bool {
+	return bits.Len(uint(index+1))%2 == 1
+}
+
+func (h *MinMaxHeap) less(i, j int) bool {
+	return h.heap[i].Value() < h.heap[j].Value()
+}
+
+func (h *MinMaxHeap) greater(i, j int) bool {
+	return h.heap[i].Value() > h.heap[j].Value()
+}
+
+func (h *MinMaxHeap) swap(i, j int) {
+	h.heap[i], h.heap[j] = h.heap[j], h.heap[i]
+}
+
+func (h *MinMaxHeap) shiftUp(index int) {
+	if index == 0 {
+		return
+	}
+
+	parent := (index - 1) / 2
+	if isMinLevel(index) {
+		if h.greater(index, parent) {
+			h.swap(index, parent)
+			h.shiftUpLevel(parent, h.greater)
+		} else {
+			h.shiftUpLevel(index, h.less)
+		}
+	} else {
+		if h.less(index, parent) {
+			h.swap(index, parent)
+			h.shiftUpLevel(parent, h.less)
+		} else {
+			h.shiftUpLevel(index, h.greater)
+		}
+	}
+}
+
+// shiftUpLevel 沿祖父节点上移
+func (h *MinMaxHeap) shiftUpLevel(index int, before func(i, j int) bool) {
+	for index > 2 {
+		grandparent := ((index-1)/2 - 1) / 2
+		if !before(index, grandparent) {
+			return
+		}
+		h.swap(index, grandparent)
+		index = grandparent
+	}
+}
+
+func (h *MinMaxHeap) shiftDown(index int) {
+	if isMinLevel(index) {
+		h.shiftDownLevel(index, h.less)
+	} else {
+		h.shiftDownLevel(index, h.greater)
+	}
+}
+
+// shiftDownLevel 在子节点与孙节点中寻找最优者并下移
+func (h *MinMaxHeap) shiftDownLevel(index int, before func(i, j int) bool) {
+	for {
+		first := index*2 + 1
+		if first >= len(h.heap) {
+			return
+		}
+
+		// 候选：两个子节点及四个孙节点
+		target := first
+		for _, c := range [...]int{first + 1, first*2 + 1, first*2 + 2, first*2 + 3, first*2 + 4} {
+			if c < len(h.heap) && before(c, target) {
+				target = c
+			}
+		}
+
+		if !before(target, index) {
+			return
+		}
+		h.swap(target, index)
+		if target <= first+1 { // 子节点
+			return
+		}
+
+		parent := (target - 1) / 2
+		if before(parent, target) {
+			h.swap(target, parent)
+		}
+		index = target
+	}
+}
+
+// maxIndex 最大值所在的下标
+func (h *MinMaxHeap) maxIndex() int {
+	switch len(h.heap) {
+	case 1:
+		return 0
+	case 2:
+		return 1
+	}
+	if h.greater(2, 1) {
+		return 2
+	}
+	return 1
+}
+
+func (h *MinMaxHeap) removeAt(index int) Interface {
+	val := h.heap[index]
+	last := len(h.heap) - 1
+	h.heap[index] = h.heap[last]
+	h.heap[last] = nil
+	h.heap = h.heap[:last]
+	if index < last {
+		h.shiftDown(index)
+	}
+	return val
+}
+
+// Insert 入堆
+func (h *MinMaxHeap) Insert(val Interface) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	h.heap = append(h.heap, val)
+	h.shiftUp(len(h.heap) - 1)
+}
+
+// PeekMin 返回最小元素不删除
+func (h *MinMaxHeap) PeekMin() (val Interface, err error) {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	if len(h.heap) == 0 {
+		return nil, ErrEmpty
+	}
+	return h.heap[0], nil
+}
+
+// PeekMax 返回最大元素不删除
+func (h *MinMaxHeap) PeekMax() (val Interface, err error) {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	if len(h.heap) == 0 {
+		return nil, ErrEmpty
+	}
+	return h.heap[h.maxIndex()], nil
+}
+
+// PopMin 返回最小元素并删除
+func (h *MinMaxHeap) PopMin() (val Interface, err error) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if len(h.heap) == 0 {
+		return nil, ErrEmpty
+	}
+	return h.removeAt(0), nil
+}
+
+// PopMax 返回最大元素并删除
+func (h *MinMaxHeap) PopMax() (val Interface, err error) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+	if len(h.heap) == 0 {
+		return nil, ErrEmpty
+	}
+	return h.removeAt(h.maxIndex()), nil
+}
+
+// Size .
+func (h *MinMaxHeap) Size() int {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	return len(h.heap)
+}
@@ -0,0 +1,99 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestMinMaxHeap(t *testing.T) {
+	heap := NewMinMaxHeap()
+	testdata := []value{79, 66, 43, 83, 30, 87, 38, 55, 91, 72, 49, 9}
+	for _, val := range testdata {
+		heap.Insert(val)
+	}
+
+	if v, _ := heap.PeekMin(); v.Value() != 9 {
+		t.Fatalf("min: %f", v.Value())
+	}
+	if v, _ := heap.PeekMax(); v.Value() != 91 {
+		t.Fatalf("max: %f", v.Value())
+	}
+
+	// 交替从两端弹出
+	mins := []float64{9, 30, 38, 43, 49, 55}
+	maxs := []float64{91, 87, 83, 79, 72, 66}
+	for i := range mins {
+		v, err := heap.PopMin()
+		if err != nil || v.Value() != mins[i] {
+			t.Fatalf("%f --> %v", mins[i], v)
+		}
+		v, err = heap.PopMax()
+		if err != nil || v.Value() != maxs[i] {
+			t.Fatalf("%f --> %v", maxs[i], v)
+		}
+	}
+
+	if _, err := heap.PopMin(); err != ErrEmpty {
+		t.Fatal("heap should be empty")
+	}
+	if _, err := heap.PeekMax(); err != ErrEmpty {
+		t.Fatal("heap should be empty")
+	}
+}
+
+func TestMinMaxHeapRandom(t *testing.T) {
+	heap := NewMinMaxHeap()
+	var ref []float64
+	for i := 0; i < 5000; i++ {
+		if rand.Intn(3) > 0 || len(ref) == 0 {
+			v := float64(rand.Intn(1000))
+			heap.Insert(value(v))
+			ref = append(ref, v)
+			sort.Float64s(ref)
+			continue
+		}
+
+		if rand.Intn(2) == 0 {
+			v, _ := heap.PopMin()
+			if v.Value() != ref[0] {
+				t.Fatalf("min: %f --> %f", ref[0], v.Value())
+			}
+			ref = ref[1:]
+		} else {
+			v, _ := heap.PopMax()
+			if v.Value() != ref[len(ref)-1] {
+				t.Fatalf("max: %f --> %f", ref[len(ref)-1], v.Value())
+			}
+			ref = ref[:len(ref)-1]
+		}
+		if heap.Size() != len(ref) {
+			t.Fatalf("size: %d --> %d", len(ref), heap.Size())
+		}
+	}
+}
+
+func TestMultiprocessMinMaxHeap(t *testing.T) {
+	heap := NewMinMaxHeap()
+	wg := new(sync.WaitGroup)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(base int) {
+			for j := 0; j < 100; j++ {
+				heap.Insert(value(base*100 + j))
+			}
+			wg.Done()
+		}(i)
+	}
+	wg.Wait()
+
+	for lo, hi := 0.0, 399.0; lo < hi; lo, hi = lo+1, hi-1 {
+		if v, _ := heap.PopMin(); v.Value() != lo {
+			t.Fatalf("min: %f --> %f", lo, v.Value())
+		}
+		if v, _ := heap.PopMax(); v.Value() != hi {
+			t.Fatalf("max: %f --> %f", hi, v.Value())
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package heap
+
+// plainHeap 不加锁、不维护 key 索引的二叉堆，由调用方负责同步
+type plainHeap struct {
+	heap   []*entry
+	before func(a, b *entry) bool // a 是否应排在 b 之前
+}
+
+func newPlainHeap(before func(a, b *entry) bool) *plainHeap {
+	return &plainHeap{before: before}
+}
+
+// byValue 按排序指标比较
+func byValue(t T) func(a, b *entry) bool {
+	if t == MaxHeap {
+		return func(a, b *entry) bool { return a.v > b.v }
+	}
+	return func(a, b *entry) bool { return a.v < b.v }
+}
+
+func (h *plainHeap) len() int {
+	return len(h.heap)
+}
+
+// top 返回堆顶元素，堆为空时返回 nil
+func (h *plainHeap) top() *entry {
+	if len(h.heap) == 0 {
+		return nil
+	}
+	return h.heap[0]
+}
+
+func (h *plainHeap) push(e *entry) {
+	h.heap = append(h.heap, e)
+	index := len(h.heap) - 1
+	for index > 0 {
+		parent := (index - 1) / 2
+		if !h.before(h.heap[index], h.heap[parent]) {
+			return
+		}
+		h.heap[index], h.heap[parent] = h.heap[parent], h.heap[index]
+		index = parent
+	}
+}
+
+// pop 删除并返回堆顶元素，调用方需保证堆不为空
+func (h *plainHeap) pop() *entry {
+	e := h.heap[0]
+	last := len(h.heap) - 1
+	h.heap[0] = h.heap[last]
+	h.heap[last] = nil
+	h.heap = h.heap[:last]
+
+	index := 0
+	for {
+		target, left, right := index, index*2+1, index*2+2
+		if left < last && h.before(h.heap[left], h.heap[target]) {
+			target = left
+		}
+		if right < last && h.before(h.heap[right], h.heap[target]) {
+			target = right
+		}
+		if target == index {
+			return e
+		}
+		h.heap[index], h.heap[target] = h.heap[target], h.heap[index]
+		index = target
+	}
+}
@@ -0,0 +1,117 @@
+package heap
+
+import (
+	"math"
+	"sync"
+)
+
+// QuantileTracker 使用一对堆跟踪数据流的 q 分位数
+//	lower 为最大堆，保存排名不超过 floor(q*(n-1)) 的元素；upper 为最小堆，保存其余元素。
+//	分位数在 lower 堆顶与 upper 堆顶之间线性插值，Add/Remove 的复杂度为 O(log n)
+type QuantileTracker struct {
+	q     float64
+	lower *BinaryHeap
+	upper *BinaryHeap
+
+	mux sync.RWMutex
+}
+
+// NewQuantileTracker 创建 q 分位数跟踪器，q 的范围为 [0, 1]
+func NewQuantileTracker(q float64) *QuantileTracker {
+	return &QuantileTracker{
+		q:     math.Max(0, math.Min(1, q)),
+		lower: NewBinaryHeap(MaxHeap),
+		upper: NewBinaryHeap(MinHeap),
+	}
+}
+
+// Add 添加一个样本
+func (qt *QuantileTracker) Add(val Interface) {
+	qt.mux.Lock()
+	defer qt.mux.Unlock()
+
+	if top, err := qt.lower.Peek(); err == nil && val.Value() <= top.Value() {
+		qt.lower.Insert(val)
+	} else {
+		qt.upper.Insert(val)
+	}
+	qt.rebalance()
+}
+
+// Remove 根据 key 删除样本（如滑出窗口的样本），key 不存在时返回 nil
+func (qt *QuantileTracker) Remove(key any) Interface {
+	qt.mux.Lock()
+	defer qt.mux.Unlock()
+
+	val := qt.lower.Delete(key)
+	if val == nil {
+		val = qt.upper.Delete(key)
+	}
+	if val != nil {
+		qt.rebalance()
+	}
+	return val
+}
+
+// rank 分位数所在的排名（从 0 开始）
+func (qt *QuantileTracker) rank(n int) float64 {
+	return qt.q * float64(n-1)
+}
+
+// rebalance 调整两个堆的大小，使 lower 恰好保存 floor(rank)+1 个元素
+func (qt *QuantileTracker) rebalance() {
+	n := qt.lower.Size() + qt.upper.Size()
+	target := 0
+	if n > 0 {
+		target = int(math.Floor(qt.rank(n))) + 1
+	}
+
+	for qt.lower.Size() > target {
+		val, _ := qt.lower.Pop()
+		qt.upper.Insert(val)
+	}
+	for qt.lower.Size() < target {
+		val, _ := qt.upper.Pop()
+		qt.lower.Insert(val)
+	}
+}
+
+// Quantile 返回当前的 q 分位数，没有样本时返回 ErrEmpty
+func (qt *QuantileTracker) Quantile() (float64, error) {
+	qt.mux.RLock()
+	defer qt.mux.RUnlock()
+
+	a, err := qt.lower.Peek()
+	if err != nil {
+		return 0, ErrEmpty
+	}
+	n := qt.lower.Size() + qt.upper.Size()
+	frac := qt.rank(n) - math.Floor(qt.rank(n))
+	b, err := qt.upper.Peek()
+	if frac == 0 || err != nil {
+		return a.Value(), nil
+	}
+	return a.Value() + (b.Value()-a.Value())*frac, nil
+}
+
+// Size 样本个数
+func (qt *QuantileTracker) Size() int {
+	qt.mux.RLock()
+	defer qt.mux.RUnlock()
+	return qt.lower.Size() + qt.upper.Size()
+}
+
+// MedianTracker 中位数跟踪器
+type MedianTracker struct {
+	*QuantileTracker
+}
+
+// NewMedianTracker 创建中位数跟踪器
+func NewMedianTracker() *MedianTracker {
+	return &MedianTracker{NewQuantileTracker(0.5)}
+}
+
+// Median 返回当前的中位数，样本个数为偶数时取中间两个样本的平均值
+func (mt *MedianTracker) Median() (float64, error) {
+	return mt.Quantile()
+}
@@ -0,0 +1,71 @@
+package heap
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+// quantile 排序后线性插值计算 q 分位数
+func quantile(values []float64, q float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	rank := q * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	if lo == len(sorted)-1 {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[lo+1]-sorted[lo])*(rank-float64(lo))
+}
+
+func TestMedianTracker(t *testing.T) {
+	mt := NewMedianTracker()
+	if _, err := mt.Median(); err != ErrEmpty {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+
+	for i, c := range []struct{ v, median float64 }{
+		{5, 5}, {15, 10}, {1, 5}, {3, 4}, {8, 5}, {7, 6},
+	} {
+		mt.Add(&TValue{key: int64(i), val: c.v})
+		if m, _ := mt.Median(); m != c.median {
+			t.Fatalf("add %f: median %f --> %f", c.v, c.median, m)
+		}
+	}
+
+	// 删除 15 和 1 后剩余 3 5 7 8
+	mt.Remove(int64(1))
+	mt.Remove(int64(2))
+	if mt.Remove(int64(1)) != nil {
+		t.Fatal("key 1 should be removed")
+	}
+	if m, _ := mt.Median(); m != 6 || mt.Size() != 4 {
+		t.Fatalf("median: %f, size: %d", m, mt.Size())
+	}
+}
+
+func TestQuantileTrackerSlidingWindow(t *testing.T) {
+	const window = 50
+	for _, q := range []float64{0, 0.1, 0.5, 0.9, 0.99, 1} {
+		qt := NewQuantileTracker(q)
+		var samples []float64
+		for i := 0; i < 1000; i++ {
+			v := math.Round(rand.ExpFloat64() * 100)
+			qt.Add(&TValue{key: int64(i), val: v})
+			samples = append(samples, v)
+			if len(samples) > window {
+				qt.Remove(int64(i - window))
+				samples = samples[1:]
+			}
+
+			got, err := qt.Quantile()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := quantile(samples, q); math.Abs(got-want) > 1e-9 {
+				t.Fatalf("q %f, step %d: %f --> %f", q, i, want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package heap
+
+// PriorityQueue 优先队列，BinaryHeap 与 FibHeap 均实现了该接口
+type PriorityQueue interface {
+	// Insert 插入一个元素
+	//	key 可以重复，此时 Update/Delete 作用于其中最早加入队列的一个；
+	//	Merge 移入的元素视为按其在 other 中加入的先后顺序依次加入
+	Insert(val Interface) error
+	// Pop 返回并移除堆顶元素
+	Pop() (Interface, error)
+	// Peek 返回堆顶元素（不移除）
+	Peek() (Interface, error)
+	// Size 元素个数
+	Size() int
+	// Update 根据元素的 key 更新其值，key 不存在时返回 ErrNotFound
+	Update(val Interface) error
+	// Delete 根据 key 删除元素，key 不存在时返回 nil
+	Delete(key any) Interface
+	// Contains 是否存在 key
+	Contains(key any) bool
+	// Merge 将 other 中的元素全部移入当前队列，other 会被清空
+	//	返回错误时两个队列均不变
+	Merge(other PriorityQueue) error
+	// Range 以任意顺序遍历全部元素，f 返回 false 时停止
+	Range(f func(val Interface) bool)
+}
+
+var (
+	_ PriorityQueue = (*BinaryHeap)(nil)
+	_ PriorityQueue = (*FibHeap)(nil)
+)
+
+// drain 取出 pq 中的全部元素并清空 pq
+//	BinaryHeap 与 FibHeap 按加入顺序返回，其他实现按出堆顺序返回
+func drain(pq PriorityQueue) []Interface {
+	switch h := pq.(type) {
+	case *BinaryHeap:
+		return h.take()
+	case *FibHeap:
+		return h.take()
+	}
+
+	values := make([]Interface, 0, pq.Size())
+	for {
+		val, err := pq.Pop()
+		if err != nil {
+			return values
+		}
+		values = append(values, val)
+	}
+}
@@ -0,0 +1,150 @@
+package heap
+
+import (
+	"errors"
+	"math"
+	"math/bits"
+	"sync"
+)
+
+var (
+	// ErrNotMonotone priority is less than the last popped priority
+	ErrNotMonotone = errors.New("priority is less than the last popped priority")
+	// ErrInvalidPriority priority must be a non-negative integer
+	ErrInvalidPriority = errors.New("priority must be a non-negative integer")
+)
+
+type radixItem struct {
+	value Interface
+	key   uint64
+}
+
+// RadixHeap 基数堆（最小堆）
+//	元素的排序指标必须是非负整数，且不能小于最近一次出堆的排序指标（单调性），
+//	适用于 Dijkstra 等排序指标单调递增的场景。
+//	第 i 个桶保存与 last 的最高不同位为第 i 位的元素，Pop 的均摊复杂度为 O(log C)
+type RadixHeap struct {
+	buckets [65][]radixItem
+	last    uint64 // 最近一次出堆的排序指标
+	size    int
+
+	mux sync.RWMutex
+}
+
+// NewRadixHeap 创建基数堆
+func NewRadixHeap() *RadixHeap {
+	return &RadixHeap{}
+}
+
+func (h *RadixHeap) bucket(key uint64) int {
+	return bits.Len64(key ^ h.last)
+}
+
+// Insert 插入一个元素
+//	排序指标小于最近一次出堆的排序指标时返回 ErrNotMonotone
+func (h *RadixHeap) Insert(val Interface) error {
+	v := val.Value()
+	if v < 0 || v != math.Trunc(v) || v >= 1<<64 {
+		return ErrInvalidPriority
+	}
+	key := uint64(v)
+
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if key < h.last {
+		return ErrNotMonotone
+	}
+	i := h.bucket(key)
+	h.buckets[i] = append(h.buckets[i], radixItem{value: val, key: key})
+	h.size++
+	return nil
+}
+
+// Pop 返回并移除排序指标最小的元素
+func (h *RadixHeap) Pop() (val Interface, err error) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	if h.size == 0 {
+		return nil, ErrEmpty
+	}
+
+	if len(h.buckets[0]) == 0 {
+		h.redistribute()
+	}
+	b := h.buckets[0]
+	item := b[len(b)-1]
+	b[len(b)-1] = radixItem{}
+	h.buckets[0] = b[:len(b)-1]
+	h.size--
+	return item.value, nil
+}
+
+// redistribute 以第一个非空桶的最小值作为 last，并将该桶的元素分配到更低的桶中
+func (h *RadixHeap) redistribute() {
+	i := 1
+	for len(h.buckets[i]) == 0 {
+		i++
+	}
+
+	b := h.buckets[i]
+	h.last = minRadixKey(b)
+	for _, item := range b {
+		j := h.bucket(item.key)
+		h.buckets[j] = append(h.buckets[j], item)
+	}
+	for k := range b {
+		b[k] = radixItem{}
+	}
+	h.buckets[i] = b[:0]
+}
+
+func minRadixKey(items []radixItem) uint64 {
+	min := items[0].key
+	for _, item := range items[1:] {
+		if item.key < min {
+			min = item.key
+		}
+	}
+	return min
+}
+
+// Peek 返回排序指标最小的元素（不移除）
+func (h *RadixHeap) Peek() (val Interface, err error) {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	if h.size == 0 {
+		return nil, ErrEmpty
+	}
+
+	for _, b := range h.buckets {
+		if len(b) == 0 {
+			continue
+		}
+		// 与 Pop 一致，相同指标的元素中后插入的先出堆
+		item := b[0]
+		for _, other := range b[1:] {
+			if other.key <= item.key {
+				item = other
+			}
+		}
+		return item.value, nil
+	}
+	return nil, ErrEmpty
+}
+
+// Last 最近一次出堆的排序指标，新元素的排序指标不能小于该值
+func (h *RadixHeap) Last() uint64 {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	return h.last
+}
+
+// Size .
+func (h *RadixHeap) Size() int {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+	return h.size
+}
@@ -0,0 +1,99 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"testing"
+)
+
+func TestRadixHeap(t *testing.T) {
+	heap := NewRadixHeap()
+	for _, val := range []value{79, 66, 43, 83, 30, 87, 38, 55, 91, 72, 49, 9, 9} {
+		if err := heap.Insert(val); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	result := []float64{9, 9, 30, 38, 43, 49, 55, 66, 72, 79, 83, 87, 91}
+	for i := 0; i < 5; i++ {
+		peek, _ := heap.Peek()
+		val, err := heap.Pop()
+		if err != nil || val.Value() != result[i] || peek != val {
+			t.Fatalf("%f --> %v (peek %v)", result[i], val, peek)
+		}
+	}
+
+	if err := heap.Insert(value(42)); err != ErrNotMonotone {
+		t.Fatalf("expected ErrNotMonotone, got %v", err)
+	}
+	for _, val := range []value{-1, 1.5} {
+		if err := heap.Insert(val); err != ErrInvalidPriority {
+			t.Fatalf("expected ErrInvalidPriority, got %v", err)
+		}
+	}
+	heap.Insert(value(43))
+	heap.Insert(value(50))
+
+	result = []float64{43, 49, 50, 55, 66, 72, 79, 83, 87, 91}
+	for _, want := range result {
+		val, _ := heap.Pop()
+		if val.Value() != want {
+			t.Fatalf("%f --> %f", want, val.Value())
+		}
+	}
+	if _, err := heap.Pop(); err != ErrEmpty || heap.Size() != 0 {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+}
+
+func TestRadixHeapMonotone(t *testing.T) {
+	heap := NewRadixHeap()
+	var ref []float64
+	for i := 0; i < 10000; i++ {
+		if rand.Intn(2) == 0 || len(ref) == 0 {
+			// 插入不小于 last 的随机值
+			v := float64(heap.Last() + uint64(rand.Intn(1<<20)))
+			heap.Insert(value(v))
+			ref = append(ref, v)
+			sort.Float64s(ref)
+			continue
+		}
+		val, _ := heap.Pop()
+		if val.Value() != ref[0] {
+			t.Fatalf("%f --> %f", ref[0], val.Value())
+		}
+		ref = ref[1:]
+	}
+	if heap.Size() != len(ref) {
+		t.Fatalf("size: %d --> %d", len(ref), heap.Size())
+	}
+}
+
+// benchmarkMonotone 模拟 Dijkstra 中排序指标单调递增的负载
+func benchmarkMonotone(b *testing.B, insert func(Interface), pop func() Interface) {
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		insert(value(r.Intn(1000)))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		top := pop()
+		insert(value(int(top.Value()) + r.Intn(1000)))
+	}
+}
+
+func BenchmarkRadixHeapMonotone(b *testing.B) {
+	h := NewRadixHeap()
+	benchmarkMonotone(b, func(val Interface) { h.Insert(val) }, func() Interface {
+		val, _ := h.Pop()
+		return val
+	})
+}
+
+func BenchmarkBinaryHeapMonotone(b *testing.B) {
+	h := NewBinaryHeap(MinHeap)
+	benchmarkMonotone(b, func(val Interface) { h.Insert(val) }, func() Interface {
+		val, _ := h.Pop()
+		return val
+	})
+}
@@ -0,0 +1,219 @@
+package heap
+
+import (
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Codec 元素编解码器，用于保存和恢复堆
+type Codec interface {
+	Encode(val Interface) ([]byte, error)
+	Decode(data []byte) (Interface, error)
+}
+
+// jsonCodec 使用 encoding/json 编解码元素
+type jsonCodec struct {
+	newValue func() Interface
+}
+
+// NewJSONCodec 创建使用 encoding/json 的元素编解码器
+//	newValue 返回一个用于解码的新元素（通常是指针）
+func NewJSONCodec(newValue func() Interface) Codec {
+	return &jsonCodec{newValue: newValue}
+}
+
+func (c *jsonCodec) Encode(val Interface) ([]byte, error) {
+	return json.Marshal(val)
+}
+
+func (c *jsonCodec) Decode(data []byte) (Interface, error) {
+	val := c.newValue()
+	if err := json.Unmarshal(data, val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// Format 堆的保存格式
+type Format int
+
+const (
+	// FormatBinary 二进制格式（encoding/gob）
+	FormatBinary Format = iota
+	// FormatJSON JSON 格式
+	FormatJSON
+)
+
+var errFormat = errors.New("unknown snapshot format")
+
+func encodeSnapshot(w io.Writer, format Format, snapshot any) error {
+	switch format {
+	case FormatBinary:
+		return gob.NewEncoder(w).Encode(snapshot)
+	case FormatJSON:
+		return json.NewEncoder(w).Encode(snapshot)
+	}
+	return errFormat
+}
+
+func decodeSnapshot(r io.Reader, format Format, snapshot any) error {
+	switch format {
+	case FormatBinary:
+		return gob.NewDecoder(r).Decode(snapshot)
+	case FormatJSON:
+		return json.NewDecoder(r).Decode(snapshot)
+	}
+	return errFormat
+}
+
+type binarySnapshot struct {
+	Type    T
+	Stable  bool
+	Seq     uint64
+	Entries []binarySnapshotEntry // 按数组顺序保存
+}
+
+type binarySnapshotEntry struct {
+	Data []byte
+	Seq  uint64
+}
+
+// Save 以 format 格式保存堆，元素由 codec 编码
+func (h *BinaryHeap) Save(w io.Writer, format Format, codec Codec) error {
+	h.mux.RLock()
+	snapshot := binarySnapshot{
+		Type:    h.t,
+		Stable:  h.stable,
+		Seq:     h.seq,
+		Entries: make([]binarySnapshotEntry, len(h.heap)),
+	}
+	for i, e := range h.heap {
+		data, err := codec.Encode(e.value)
+		if err != nil {
+			h.mux.RUnlock()
+			return err
+		}
+		snapshot.Entries[i] = binarySnapshotEntry{Data: data, Seq: e.seq}
+	}
+	h.mux.RUnlock()
+
+	return encodeSnapshot(w, format, &snapshot)
+}
+
+// LoadBinaryHeap 从 Save 保存的数据恢复堆，元素的出堆顺序与保存时完全一致
+func LoadBinaryHeap(r io.Reader, format Format, codec Codec) (*BinaryHeap, error) {
+	var snapshot binarySnapshot
+	if err := decodeSnapshot(r, format, &snapshot); err != nil {
+		return nil, err
+	}
+
+	heap := NewBinaryHeap(snapshot.Type)
+	heap.stable, heap.seq = snapshot.Stable, snapshot.Seq
+	for i, se := range snapshot.Entries {
+		val, err := codec.Decode(se.Data)
+		if err != nil {
+			return nil, err
+		}
+		e := &entry{value: val, key: val.Key(), v: val.Value(), pos: i, seq: se.Seq}
+		heap.heap = append(heap.heap, e)
+		heap.addIndex(e)
+	}
+
+	if err := heap.Validate(); err != nil {
+		return nil, fmt.Errorf("corrupted snapshot: %w", err)
+	}
+	return heap, nil
+}
+
+type fibSnapshot struct {
+	Type   T
+	Lo, Hi int64
+	Roots  []fibSnapshotNode // 第一个为堆顶
+}
+
+type fibSnapshotNode struct {
+	Data     []byte
+	Seq      int64
+	Marked   bool
+	Children []fibSnapshotNode
+}
+
+// Save 以 format 格式保存堆（包括树的结构），元素由 codec 编码
+func (h *FibHeap) Save(w io.Writer, format Format, codec Codec) error {
+	h.mux.RLock()
+	roots, err := saveList(h.main, codec)
+	lo, hi := h.lo, h.hi
+	h.mux.RUnlock()
+	if err != nil {
+		return err
+	}
+
+	return encodeSnapshot(w, format, &fibSnapshot{Type: h.t, Lo: lo, Hi: hi, Roots: roots})
+}
+
+func saveList(n *node, codec Codec) ([]fibSnapshotNode, error) {
+	var nodes []fibSnapshotNode
+	for _, p := range siblings(n) {
+		data, err := codec.Encode(p.value)
+		if err != nil {
+			return nil, err
+		}
+		children, err := saveList(p.child, codec)
+		if err != nil {
+			return nil, err
+		}
+		nodes = append(nodes, fibSnapshotNode{Data: data, Seq: p.seq, Marked: p.marked, Children: children})
+	}
+	return nodes, nil
+}
+
+// LoadFibHeap 从 Save 保存的数据恢复堆，树的结构与保存时完全一致
+func LoadFibHeap(r io.Reader, format Format, codec Codec) (*FibHeap, error) {
+	var snapshot fibSnapshot
+	if err := decodeSnapshot(r, format, &snapshot); err != nil {
+		return nil, err
+	}
+
+	heap := NewFibHeap(snapshot.Type)
+	heap.lo, heap.hi = snapshot.Lo, snapshot.Hi
+	main, err := heap.loadList(snapshot.Roots, nil, codec)
+	if err != nil {
+		return nil, err
+	}
+	heap.main = main
+
+	if err := heap.Validate(); err != nil {
+		return nil, fmt.Errorf("corrupted snapshot: %w", err)
+	}
+	return heap, nil
+}
+
+// loadList 恢复循环链表及其子树，返回第一个节点
+func (h *FibHeap) loadList(nodes []fibSnapshotNode, parent *node, codec Codec) (*node, error) {
+	var first *node
+	for _, sn := range nodes {
+		val, err := codec.Decode(sn.Data)
+		if err != nil {
+			return nil, err
+		}
+
+		n := newNode(val)
+		n.parent, n.marked, n.seq = parent, sn.Marked, sn.Seq
+		n.degree = uint(len(sn.Children))
+		h.addIndex(n)
+		h.num++
+		if n.child, err = h.loadList(sn.Children, n, codec); err != nil {
+			return nil, err
+		}
+
+		if first == nil {
+			first = n
+		} else {
+			splice(first.left, n)
+		}
+	}
+	return first, nil
+}
@@ -0,0 +1,101 @@
+package heap
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+var userCodec = NewJSONCodec(func() Interface { return new(User) })
+
+func sameOrder(t *testing.T, want, got []Interface) {
+	if len(want) != len(got) {
+		t.Fatalf("len: %d --> %d", len(want), len(got))
+	}
+	for i := range want {
+		if want[i].Key() != got[i].Key() || want[i].Value() != got[i].Value() {
+			t.Fatalf("%d: %v --> %v", i, want[i], got[i])
+		}
+	}
+}
+
+func TestBinaryHeapSaveLoad(t *testing.T) {
+	heap := NewStableBinaryHeap(MinHeap)
+	for i := 0; i < 30; i++ {
+		heap.Insert(&User{ID: i, Name: "user", Age: 20 + i%7})
+	}
+	heap.Delete(3)
+	heap.Update(&User{ID: 8, Age: 1})
+
+	for _, format := range []Format{FormatBinary, FormatJSON} {
+		buf := new(bytes.Buffer)
+		if err := heap.Save(buf, format, userCodec); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadBinaryHeap(buf, format, userCodec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !loaded.Contains(8) || loaded.Contains(3) {
+			t.Fatal("key index is not restored")
+		}
+
+		// 恢复后插入的元素仍排在相同优先级的旧元素之后
+		loaded.Insert(&User{ID: 100, Age: 20})
+		clone := heap.Clone()
+		clone.Insert(&User{ID: 100, Age: 20})
+		sameOrder(t, clone.Drain(), loaded.Drain())
+	}
+}
+
+func TestFibHeapSaveLoad(t *testing.T) {
+	heap := NewFibHeap(MaxHeap)
+	for i := 0; i < 30; i++ {
+		heap.Insert(&User{ID: i, Name: "user", Age: 20 + i%7})
+	}
+	heap.Pop()
+	heap.Update(&User{ID: 8, Age: 1})
+	heap.Delete(5)
+
+	for _, format := range []Format{FormatBinary, FormatJSON} {
+		buf := new(bytes.Buffer)
+		if err := heap.Save(buf, format, userCodec); err != nil {
+			t.Fatal(err)
+		}
+		loaded, err := LoadFibHeap(buf, format, userCodec)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if loaded.Size() != heap.Size() || !loaded.Contains(8) || loaded.Contains(5) {
+			t.Fatal("key index is not restored")
+		}
+		sameOrder(t, heap.Clone().Drain(), loaded.Drain())
+	}
+}
+
+func TestLoadCorruptedSnapshot(t *testing.T) {
+	heap := NewBinaryHeap(MinHeap)
+	heap.Insert(&User{ID: 1, Age: 1})
+	heap.Insert(&User{ID: 2, Age: 2})
+
+	buf := new(bytes.Buffer)
+	heap.Save(buf, FormatJSON, userCodec)
+	var snapshot binarySnapshot
+	json.Unmarshal(buf.Bytes(), &snapshot)
+	snapshot.Entries[0], snapshot.Entries[1] = snapshot.Entries[1], snapshot.Entries[0]
+	data, _ := json.Marshal(snapshot)
+
+	if _, err := LoadBinaryHeap(bytes.NewReader(data), FormatJSON, userCodec); err == nil {
+		t.Fatal("expected error for corrupted snapshot")
+	}
+
+	fh := NewFibHeap(MinHeap)
+	fh.Insert(&User{ID: 1, Age: 1})
+	buf.Reset()
+	fh.Save(buf, FormatJSON, userCodec)
+	dup := strings.Replace(buf.String(), `"Roots":[`, `"Roots":[{"Data":"eyJJRCI6MX0="},`, 1)
+	if _, err := LoadFibHeap(strings.NewReader(dup), FormatJSON, userCodec); err == nil {
+		t.Fatal("expected error for duplicate sequence")
+	}
+}
@@ -0,0 +1,96 @@
+package heap
+
+import "sync"
+
+// TopK 只保留最优的 K 个元素
+//	t 为 MaxHeap 时保留最大的 K 个元素，为 MinHeap 时保留最小的 K 个元素。
+//	内部使用相反类型的堆，堆顶即为当前 K 个元素中最差的一个
+type TopK struct {
+	k    int
+	t    T
+	heap *BinaryHeap
+
+	mux sync.RWMutex
+}
+
+// NewTopK 创建容量为 k 的 TopK
+func NewTopK(k int, t T) *TopK {
+	reverse := MinHeap
+	if t == MinHeap {
+		reverse = MaxHeap
+	}
+	return &TopK{
+		k:    k,
+		t:    t,
+		heap: NewBinaryHeap(reverse),
+	}
+}
+
+// better a 是否优于 b
+func (tk *TopK) better(a, b float64) bool {
+	if tk.t == MaxHeap {
+		return a > b
+	}
+	return a < b
+}
+
+// Offer 提交一个元素，返回该元素是否被保留
+func (tk *TopK) Offer(val Interface) bool {
+	tk.mux.Lock()
+	defer tk.mux.Unlock()
+	return tk.offer(val)
+}
+
+func (tk *TopK) offer(val Interface) bool {
+	if tk.k <= 0 {
+		return false
+	}
+	if tk.heap.Size() < tk.k {
+		tk.heap.Insert(val)
+		return true
+	}
+
+	worst, _ := tk.heap.Peek()
+	if !tk.better(val.Value(), worst.Value()) {
+		return false
+	}
+	tk.heap.Replace(0, val)
+	return true
+}
+
+// Sorted 按从优到差的顺序返回保留的元素，不修改 TopK
+func (tk *TopK) Sorted() []Interface {
+	tk.mux.RLock()
+	defer tk.mux.RUnlock()
+
+	// 内部堆的出堆顺序是从差到优
+	values := tk.heap.Sorted()
+	for i, j := 0, len(values)-1; i < j; i, j = i+1, j-1 {
+		values[i], values[j] = values[j], values[i]
+	}
+	return values
+}
+
+// Merge 将 other 保留的元素提交到 tk，other 保持不变
+func (tk *TopK) Merge(other *TopK) {
+	if other == tk {
+		return
+	}
+
+	values := other.Sorted()
+	tk.mux.Lock()
+	defer tk.mux.Unlock()
+	for _, val := range values {
+		if !tk.offer(val) {
+			// values 有序，之后的元素不会更优
+			return
+		}
+	}
+}
+
+// Size 当前保留的元素个数
+func (tk *TopK) Size() int {
+	tk.mux.RLock()
+	defer tk.mux.RUnlock()
+	return tk.heap.Size()
+}
@@ -0,0 +1,97 @@
+package heap
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"testing"
+)
+
+func TestTopK(t *testing.T) {
+	testdata := []value{79, 66, 43, 83, 30, 87, 38, 55, 91, 72, 49, 9}
+
+	largest := NewTopK(4, MaxHeap)
+	smallest := NewTopK(4, MinHeap)
+	for _, val := range testdata {
+		largest.Offer(val)
+		smallest.Offer(val)
+	}
+
+	for name, c := range map[string]struct {
+		topk   *TopK
+		result []float64
+	}{
+		"largest":  {largest, []float64{91, 87, 83, 79}},
+		"smallest": {smallest, []float64{9, 30, 38, 43}},
+	} {
+		sorted := c.topk.Sorted()
+		if len(sorted) != len(c.result) {
+			t.Fatalf("%s: len %d", name, len(sorted))
+		}
+		for i, val := range sorted {
+			if val.Value() != c.result[i] {
+				t.Fatalf("%s: %f --> %f", name, c.result[i], val.Value())
+			}
+		}
+	}
+
+	if largest.Offer(value(1)) {
+		t.Fatal("1 should not be kept")
+	}
+	if !largest.Offer(value(100)) || largest.Size() != 4 {
+		t.Fatal("100 should be kept")
+	}
+}
+
+func TestTopKMerge(t *testing.T) {
+	a := NewTopK(3, MaxHeap)
+	b := NewTopK(3, MaxHeap)
+	for _, val := range []value{1, 8, 5, 3} {
+		a.Offer(val)
+	}
+	for _, val := range []value{7, 2, 9, 4} {
+		b.Offer(val)
+	}
+
+	a.Merge(b)
+	result := []float64{9, 8, 7}
+	for i, val := range a.Sorted() {
+		if val.Value() != result[i] {
+			t.Fatalf("%f --> %f", result[i], val.Value())
+		}
+	}
+	if b.Size() != 3 {
+		t.Fatalf("b size: %d", b.Size())
+	}
+}
+
+func TestMultiprocessTopK(t *testing.T) {
+	topk := NewTopK(10, MaxHeap)
+	all := make([]float64, 0, 4000)
+	wg := new(sync.WaitGroup)
+	data := make([][]float64, 4)
+	for i := range data {
+		for j := 0; j < 1000; j++ {
+			v := rand.Float64()
+			data[i] = append(data[i], v)
+			all = append(all, v)
+		}
+	}
+	for i := range data {
+		wg.Add(1)
+		go func(l []float64) {
+			for _, v := range l {
+				topk.Offer(value(v))
+			}
+			wg.Done()
+		}(data[i])
+	}
+	wg.Wait()
+
+	sort.Sort(sort.Reverse(sort.Float64Slice(all)))
+	for i, val := range topk.Sorted() {
+		if val.Value() != all[i] {
+			t.Fatalf("%f --> %f", all[i], val.Value())
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package heap
+
+import "fmt"
+
+// Validate 检查二叉堆的内部结构，用于调试
+//	包括堆序、元素下标以及 key 索引的一致性
+func (h *BinaryHeap) Validate() error {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	for i, e := range h.heap {
+		if e.pos != i {
+			return fmt.Errorf("entry at %d records position %d", i, e.pos)
+		}
+		if i > 0 && h.before(e, h.heap[(i-1)/2]) {
+			return fmt.Errorf("heap order violated at %d", i)
+		}
+		if h.stable && e.seq >= h.seq {
+			return fmt.Errorf("entry at %d has sequence %d beyond %d", i, e.seq, h.seq)
+		}
+
+		entries := h.index[e.key]
+		if e.slot >= len(entries) || entries[e.slot] != e {
+			return fmt.Errorf("index of key %v is inconsistent", e.key)
+		}
+	}
+
+	count := 0
+	for key, entries := range h.index {
+		if len(entries) == 0 {
+			return fmt.Errorf("empty index of key %v", key)
+		}
+		for _, e := range entries {
+			if e.key != key || e.pos >= len(h.heap) || h.heap[e.pos] != e {
+				return fmt.Errorf("index of key %v refers to a removed entry", key)
+			}
+		}
+		count += len(entries)
+	}
+	if count != len(h.heap) {
+		return fmt.Errorf("index holds %d entries, heap holds %d", count, len(h.heap))
+	}
+	return nil
+}
+
+// Validate 检查斐波那契堆的内部结构，用于调试
+//	包括堆序、度数、父节点指针、兄弟链表以及 key 索引的一致性
+func (h *FibHeap) Validate() error {
+	h.mux.RLock()
+	defer h.mux.RUnlock()
+
+	if h.main == nil {
+		if h.num != 0 || len(h.index) != 0 {
+			return fmt.Errorf("empty root list with %d nodes", h.num)
+		}
+		return nil
+	}
+
+	count, err := h.validateList(h.main, nil)
+	if err != nil {
+		return err
+	}
+	for _, root := range siblings(h.main) {
+		if h.compare(root.v, h.main.v) && root.v != h.main.v {
+			return fmt.Errorf("root %v precedes main %v", root.key, h.main.key)
+		}
+	}
+	if count != h.num {
+		return fmt.Errorf("found %d nodes, num is %d", count, h.num)
+	}
+
+	var indexed uint
+	seqs := make(map[int64]struct{}, h.num)
+	for key, nodes := range h.index {
+		if len(nodes) == 0 {
+			return fmt.Errorf("empty index of key %v", key)
+		}
+		for _, p := range nodes {
+			if p.key != key {
+				return fmt.Errorf("index of key %v refers to key %v", key, p.key)
+			}
+			if p.seq < h.lo || p.seq >= h.hi {
+				return fmt.Errorf("node %v has sequence %d beyond [%d, %d)", key, p.seq, h.lo, h.hi)
+			}
+			if _, dup := seqs[p.seq]; dup {
+				return fmt.Errorf("node %v has duplicate sequence %d", key, p.seq)
+			}
+			seqs[p.seq] = struct{}{}
+		}
+		indexed += uint(len(nodes))
+	}
+	if indexed != h.num {
+		return fmt.Errorf("index holds %d nodes, num is %d", indexed, h.num)
+	}
+	return nil
+}
+
+// validateList 检查以 n 为入口的循环链表及其子树，返回节点个数
+func (h *FibHeap) validateList(n, parent *node) (uint, error) {
+	var count uint
+	for _, p := range siblings(n) {
+		if p.right.left != p || p.left.right != p {
+			return 0, fmt.Errorf("broken sibling links at %v", p.key)
+		}
+		if p.parent != parent {
+			return 0, fmt.Errorf("node %v has wrong parent", p.key)
+		}
+		if parent != nil && !h.compare(parent.v, p.v) {
+			return 0, fmt.Errorf("heap order violated between %v and %v", parent.key, p.key)
+		}
+		if nodes := h.index[p.key]; p.slot >= len(nodes) || nodes[p.slot] != p {
+			return 0, fmt.Errorf("index of key %v is inconsistent", p.key)
+		}
+
+		degree := uint(len(siblings(p.child)))
+		if degree != p.degree {
+			return 0, fmt.Errorf("node %v has %d children, degree is %d", p.key, degree, p.degree)
+		}
+
+		count++
+		if p.child != nil {
+			c, err := h.validateList(p.child, p)
+			if err != nil {
+				return 0, err
+			}
+			count += c
+		}
+	}
+	return count, nil
+}
@@ -0,0 +1,173 @@
+package heap
+
+import (
+	"testing"
+)
+
+// refModel 参考模型，记录每个 key 当前的排序指标
+type refModel struct {
+	t      T
+	values map[int64]float64
+}
+
+// checkTop 检查 val 是否为参考模型中的最优元素
+func (m *refModel) checkTop(t *testing.T, val Interface) {
+	key := val.Key().(int64)
+	if v, ok := m.values[key]; !ok || v != val.Value() {
+		t.Fatalf("key %d: model has %v, heap returns %f", key, v, val.Value())
+	}
+	for k, v := range m.values {
+		if (m.t == MinHeap && v < val.Value()) || (m.t == MaxHeap && v > val.Value()) {
+			t.Fatalf("key %d (%f) should precede key %d (%f)", k, v, key, val.Value())
+		}
+	}
+}
+
+// ops 将 data 解析为 (操作, key, 排序指标) 序列
+func ops(data []byte, f func(op byte, key int64, v float64)) {
+	for i := 0; i+2 < len(data); i += 3 {
+		f(data[i]%6, int64(data[i+1]%64), float64(int8(data[i+2])))
+	}
+}
+
+func fuzzSeeds(f *testing.F) {
+	f.Add([]byte{0, 1, 10, 0, 2, 20, 0, 3, 5, 3, 0, 0, 1, 2, 1, 2, 3, 0})
+	f.Add([]byte{1, 0, 0, 0, 5, 200, 0, 6, 100, 0, 7, 50, 4, 0, 0, 1, 7, 255, 3, 0, 0})
+	data := make([]byte, 0, 300)
+	for i := 0; i < 100; i++ {
+		data = append(data, byte(i*7), byte(i*13), byte(i*31))
+	}
+	f.Add(data)
+}
+
+func FuzzFibHeap(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) == 0 {
+			return
+		}
+		typ := T(data[0] % 2)
+		heap := NewFibHeap(typ)
+		model := &refModel{t: typ, values: make(map[int64]float64)}
+
+		ops(data[1:], func(op byte, key int64, v float64) {
+			switch op {
+			case 0, 1:
+				if _, ok := model.values[key]; !ok {
+					if err := heap.Insert(&TValue{key: key, val: v}); err != nil {
+						t.Fatalf("insert %d: %v", key, err)
+					}
+					model.values[key] = v
+				}
+			case 2:
+				if err := heap.Update(&TValue{key: key, val: v}); err == nil {
+					model.values[key] = v
+				} else if _, ok := model.values[key]; ok {
+					t.Fatalf("update %d: %v", key, err)
+				}
+			case 3:
+				val, err := heap.Pop()
+				if err != nil {
+					if len(model.values) != 0 {
+						t.Fatalf("pop: %v", err)
+					}
+					return
+				}
+				model.checkTop(t, val)
+				delete(model.values, val.Key().(int64))
+			case 4:
+				if val := heap.Delete(key); val != nil {
+					delete(model.values, key)
+				} else if _, ok := model.values[key]; ok {
+					t.Fatalf("delete %d: not found", key)
+				}
+			case 5:
+				// 合并一个包含多层树的堆，只插入 heap 中不存在的 key
+				other := NewFibHeap(typ)
+				for i := int64(0); i < 4; i++ {
+					if _, ok := model.values[key+i*16]; !ok {
+						other.Insert(&TValue{key: key + i*16, val: v + float64(i)})
+					}
+				}
+				other.Pop()
+				values := other.Sorted()
+				if err := heap.Union(other); err != nil {
+					t.Fatal(err)
+				}
+				for _, val := range values {
+					model.values[val.Key().(int64)] = val.Value()
+				}
+				if err := other.Validate(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := heap.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if heap.Size() != len(model.values) {
+				t.Fatalf("size: %d --> %d", len(model.values), heap.Size())
+			}
+		})
+	})
+}
+
+func FuzzBinaryHeap(f *testing.F) {
+	fuzzSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		if len(data) == 0 {
+			return
+		}
+		typ := T(data[0] % 2)
+		heap := NewBinaryHeap(typ)
+		if data[0]%4 >= 2 {
+			heap = NewStableBinaryHeap(typ)
+		}
+		model := &refModel{t: typ, values: make(map[int64]float64)}
+
+		ops(data[1:], func(op byte, key int64, v float64) {
+			switch op {
+			case 0, 1:
+				if _, ok := model.values[key]; !ok {
+					heap.Insert(&TValue{key: key, val: v})
+					model.values[key] = v
+				}
+			case 2:
+				if err := heap.Update(&TValue{key: key, val: v}); err == nil {
+					model.values[key] = v
+				} else if _, ok := model.values[key]; ok {
+					t.Fatalf("update %d: %v", key, err)
+				}
+			case 3:
+				val, err := heap.Pop()
+				if err != nil {
+					if len(model.values) != 0 {
+						t.Fatalf("pop: %v", err)
+					}
+					return
+				}
+				model.checkTop(t, val)
+				delete(model.values, val.Key().(int64))
+			case 4:
+				if val := heap.Delete(key); val != nil {
+					delete(model.values, key)
+				} else if _, ok := model.values[key]; ok {
+					t.Fatalf("delete %d: not found", key)
+				}
+			case 5:
+				if heap.Size() > 0 {
+					index := int(key) % heap.Size()
+					val, _ := heap.PopByIndex(index)
+					delete(model.values, val.Key().(int64))
+				}
+			}
+
+			if err := heap.Validate(); err != nil {
+				t.Fatal(err)
+			}
+			if heap.Size() != len(model.values) {
+				t.Fatalf("size: %d --> %d", len(model.values), heap.Size())
+			}
+		})
+	})
+}
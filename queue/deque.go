@@ -0,0 +1,222 @@
+package queue
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/hunyxv/datastructure/stack"
+)
+
+var (
+	// ErrEmpty queue is empty
+	ErrEmpty = errors.New("queue is empty")
+	// ErrFull queue is full
+	ErrFull = errors.New("queue is full")
+	// ErrExceed index out of range
+	ErrExceed = errors.New("index out of range")
+)
+
+// Deque double-ended queue based on a ring buffer
+type Deque[T any] struct {
+	buf    []T
+	head   int // index of the front element
+	n      int
+	size   int                // capacity of a bounded deque, initial capacity of a growable one
+	growth stack.GrowthPolicy // nil for a bounded deque
+	mux    sync.RWMutex
+}
+
+// NewDeque create new bounded deque holding at most size elements
+func NewDeque[T any](size int) *Deque[T] {
+	return &Deque[T]{
+		buf:  make([]T, size),
+		size: size,
+	}
+}
+
+// NewGrowableDeque create new unbounded deque with the initial capacity,
+// the ring buffer grows (and optionally shrinks, never below the initial
+// capacity) according to policy. stack.Doubling is used if policy is nil.
+func NewGrowableDeque[T any](size int, policy stack.GrowthPolicy) *Deque[T] {
+	if policy == nil {
+		policy = stack.Doubling
+	}
+	d := NewDeque[T](size)
+	d.growth = policy
+	return d
+}
+
+// index returns the position in buf of the i-th element from the front
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) % len(d.buf)
+}
+
+// resize reallocates the ring buffer with the capacity, the front element
+// is moved to buf[0]
+func (d *Deque[T]) resize(capacity int) {
+	if capacity <= d.n {
+		capacity = d.n + 1
+	}
+	buf := make([]T, capacity)
+	for i := 0; i < d.n; i++ {
+		buf[i] = d.buf[d.index(i)]
+	}
+	d.buf = buf
+	d.head = 0
+}
+
+// reserve makes room for one more element
+func (d *Deque[T]) reserve() error {
+	if d.n < len(d.buf) {
+		return nil
+	}
+	if d.growth == nil {
+		return ErrFull
+	}
+	d.resize(d.growth.Grow(len(d.buf)))
+	return nil
+}
+
+// shrink releases memory after a pop according to the growth policy
+func (d *Deque[T]) shrink() {
+	if d.growth == nil {
+		return
+	}
+	capacity := d.growth.Shrink(d.n, len(d.buf))
+	if capacity < d.size {
+		capacity = d.size
+	}
+	if capacity < len(d.buf) {
+		d.resize(capacity)
+	}
+}
+
+// PushFront insert element at the front, a bounded deque returns ErrFull
+// if it is full
+func (d *Deque[T]) PushFront(ele T) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if err := d.reserve(); err != nil {
+		return err
+	}
+	d.head = (d.head - 1 + len(d.buf)) % len(d.buf)
+	d.buf[d.head] = ele
+	d.n++
+	return nil
+}
+
+// PushBack insert element at the back, a bounded deque returns ErrFull
+// if it is full
+func (d *Deque[T]) PushBack(ele T) error {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	if err := d.reserve(); err != nil {
+		return err
+	}
+	d.buf[d.index(d.n)] = ele
+	d.n++
+	return nil
+}
+
+// PopFront delete and return the element at the front
+func (d *Deque[T]) PopFront() (T, error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	var zero T
+	if d.n == 0 {
+		return zero, ErrEmpty
+	}
+	el := d.buf[d.head]
+	d.buf[d.head] = zero
+	d.head = d.index(1)
+	d.n--
+	d.shrink()
+	return el, nil
+}
+
+// PopBack delete and return the element at the back
+func (d *Deque[T]) PopBack() (T, error) {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	var zero T
+	if d.n == 0 {
+		return zero, ErrEmpty
+	}
+	i := d.index(d.n - 1)
+	el := d.buf[i]
+	d.buf[i] = zero
+	d.n--
+	d.shrink()
+	return el, nil
+}
+
+// Front return the element at the front without removing it
+func (d *Deque[T]) Front() (T, error) {
+	return d.At(0)
+}
+
+// Back return the element at the back without removing it
+func (d *Deque[T]) Back() (T, error) {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	if d.n == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return d.buf[d.index(d.n-1)], nil
+}
+
+// At return the i-th element from the front, returns ErrEmpty if the deque
+// is empty and ErrExceed if i is out of range
+func (d *Deque[T]) At(i int) (T, error) {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	var zero T
+	if d.n == 0 {
+		return zero, ErrEmpty
+	}
+	if i < 0 || i >= d.n {
+		return zero, ErrExceed
+	}
+	return d.buf[d.index(i)], nil
+}
+
+// Len returns the number of elements
+func (d *Deque[T]) Len() int {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	return d.n
+}
+
+// Cap returns the current capacity of the ring buffer
+func (d *Deque[T]) Cap() int {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	return len(d.buf)
+}
+
+// IsEmpty return true if deque is empty
+func (d *Deque[T]) IsEmpty() bool {
+	return d.Len() == 0
+}
+
+// Clear remove all elements from the deque
+func (d *Deque[T]) Clear() {
+	d.mux.Lock()
+	defer d.mux.Unlock()
+	d.buf = make([]T, d.size)
+	d.head = 0
+	d.n = 0
+}
+
+// Traverse calls f sequentially for each element from the front to the back.
+// If f returns false, Traverse stops the iteration.
+func (d *Deque[T]) Traverse(f func(el T) bool) {
+	d.mux.RLock()
+	defer d.mux.RUnlock()
+	for i := 0; i < d.n; i++ {
+		if !f(d.buf[d.index(i)]) {
+			break
+		}
+	}
+}
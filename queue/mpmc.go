@@ -0,0 +1,200 @@
+package queue
+
+import (
+	"context"
+	"runtime"
+	"sync/atomic"
+	"time"
+)
+
+// mpmcCell is a slot of the ring buffer, seq tells whose turn it is:
+// seq == pos means the slot is free for the enqueuer at pos,
+// seq == pos+1 means it holds the element for the dequeuer at pos
+type mpmcCell[T any] struct {
+	seq uint64
+	val T
+}
+
+// MPMCQueue bounded lock-free multi-producer multi-consumer queue
+// (Dmitry Vyukov's ring buffer with per-slot sequence numbers).
+//
+// Producers and consumers only contend on a CAS of their own position
+// counter, elements are handed over through the slot sequence numbers.
+type MPMCQueue[T any] struct {
+	_          [64]byte // avoid false sharing
+	enqueuePos uint64
+	_          [56]byte
+	dequeuePos uint64
+	_          [56]byte
+	mask       uint64
+	buf        []mpmcCell[T]
+}
+
+// NewMPMCQueue create new queue holding at most size elements,
+// size is rounded up to a power of 2
+func NewMPMCQueue[T any](size int) *MPMCQueue[T] {
+	capacity := 2
+	for capacity < size {
+		capacity <<= 1
+	}
+	q := &MPMCQueue[T]{
+		mask: uint64(capacity - 1),
+		buf:  make([]mpmcCell[T], capacity),
+	}
+	for i := range q.buf {
+		q.buf[i].seq = uint64(i)
+	}
+	return q
+}
+
+// TryEnqueue insert element at the back of the queue without blocking,
+// returns ErrFull if the queue is full
+func (q *MPMCQueue[T]) TryEnqueue(ele T) error {
+	pos := atomic.LoadUint64(&q.enqueuePos)
+	for {
+		cell := &q.buf[pos&q.mask]
+		seq := atomic.LoadUint64(&cell.seq)
+		switch dif := int64(seq - pos); {
+		case dif == 0:
+			if atomic.CompareAndSwapUint64(&q.enqueuePos, pos, pos+1) {
+				cell.val = ele
+				atomic.StoreUint64(&cell.seq, pos+1)
+				return nil
+			}
+			pos = atomic.LoadUint64(&q.enqueuePos)
+		case dif < 0:
+			return ErrFull
+		default:
+			pos = atomic.LoadUint64(&q.enqueuePos)
+		}
+	}
+}
+
+// TryDequeue delete and return the element at the front of the queue
+// without blocking, returns ErrEmpty if the queue is empty
+func (q *MPMCQueue[T]) TryDequeue() (T, error) {
+	pos := atomic.LoadUint64(&q.dequeuePos)
+	for {
+		cell := &q.buf[pos&q.mask]
+		seq := atomic.LoadUint64(&cell.seq)
+		switch dif := int64(seq - (pos + 1)); {
+		case dif == 0:
+			if atomic.CompareAndSwapUint64(&q.dequeuePos, pos, pos+1) {
+				var zero T
+				el := cell.val
+				cell.val = zero
+				atomic.StoreUint64(&cell.seq, pos+q.mask+1)
+				return el, nil
+			}
+			pos = atomic.LoadUint64(&q.dequeuePos)
+		case dif < 0:
+			var zero T
+			return zero, ErrEmpty
+		default:
+			pos = atomic.LoadUint64(&q.dequeuePos)
+		}
+	}
+}
+
+const (
+	backoffSpins    = 64               // Gosched rounds before sleeping
+	backoffMinSleep = time.Microsecond // first sleep after spinning
+	backoffMaxSleep = time.Millisecond // sleeps double up to this
+)
+
+// backoff waits between retries of Enqueue and Dequeue: it yields for a few
+// rounds, then sleeps for exponentially growing periods, so that a waiter on
+// an idle queue does not keep a core busy
+type backoff struct {
+	spins int
+	sleep time.Duration
+	timer *time.Timer
+}
+
+// wait returns ctx.Err() if ctx is done before the wait is over
+func (b *backoff) wait(ctx context.Context) error {
+	if b.spins < backoffSpins {
+		b.spins++
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+			runtime.Gosched()
+			return nil
+		}
+	}
+
+	if b.sleep == 0 {
+		b.sleep = backoffMinSleep
+	} else if b.sleep < backoffMaxSleep {
+		b.sleep *= 2
+		if b.sleep > backoffMaxSleep {
+			b.sleep = backoffMaxSleep
+		}
+	}
+	if b.timer == nil {
+		b.timer = time.NewTimer(b.sleep)
+	} else {
+		b.timer.Reset(b.sleep)
+	}
+	select {
+	case <-ctx.Done():
+		b.timer.Stop()
+		return ctx.Err()
+	case <-b.timer.C:
+		return nil
+	}
+}
+
+// Enqueue insert element at the back of the queue, waits while the queue is
+// full and returns ctx.Err() if ctx is done first. The queue has no wakeup
+// mechanism: Enqueue polls, spinning briefly and then sleeping up to 1ms
+// between attempts, which bounds both its CPU use and its extra latency.
+func (q *MPMCQueue[T]) Enqueue(ctx context.Context, ele T) error {
+	var b backoff
+	for {
+		if q.TryEnqueue(ele) == nil {
+			return nil
+		}
+		if err := b.wait(ctx); err != nil {
+			return err
+		}
+	}
+}
+
+// Dequeue delete and return the element at the front of the queue, waits
+// while the queue is empty and returns ctx.Err() if ctx is done first. Like
+// Enqueue it polls, spinning briefly and then sleeping up to 1ms between
+// attempts.
+func (q *MPMCQueue[T]) Dequeue(ctx context.Context) (T, error) {
+	var b backoff
+	for {
+		if el, err := q.TryDequeue(); err == nil {
+			return el, nil
+		}
+		if err := b.wait(ctx); err != nil {
+			var zero T
+			return zero, err
+		}
+	}
+}
+
+// Len returns the number of elements, it is approximate while the queue
+// is being modified concurrently
+func (q *MPMCQueue[T]) Len() int {
+	enqueue := atomic.LoadUint64(&q.enqueuePos)
+	dequeue := atomic.LoadUint64(&q.dequeuePos)
+	n := int64(enqueue - dequeue)
+	if n < 0 {
+		return 0
+	}
+	if n > int64(len(q.buf)) {
+		return len(q.buf)
+	}
+	return int(n)
+}
+
+// Cap returns the capacity of the queue
+func (q *MPMCQueue[T]) Cap() int {
+	return len(q.buf)
+}
@@ -0,0 +1,196 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMPMCQueue(t *testing.T) {
+	queue := NewMPMCQueue[int](3)
+	if queue.Cap() != 4 {
+		t.Fatalf("cap: %d", queue.Cap())
+	}
+	if _, err := queue.TryDequeue(); err != ErrEmpty {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if err := queue.TryEnqueue(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := queue.TryEnqueue(4); err != ErrFull || queue.Len() != 4 {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+
+	// 环形缓冲区回绕
+	for i := 0; i < 20; i++ {
+		if el, err := queue.TryDequeue(); err != nil || el != i {
+			t.Fatalf("%d --> %d", i, el)
+		}
+		queue.TryEnqueue(i + 4)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := queue.Enqueue(ctx, 100); err != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+// 空闲时 Dequeue 先自旋再休眠，休眠时间以指数增长到 backoffMaxSleep
+func TestMPMCQueueBackoff(t *testing.T) {
+	var b backoff
+	for i := 0; i < backoffSpins; i++ {
+		b.wait(context.Background())
+	}
+	if b.sleep != 0 {
+		t.Fatalf("slept while spinning: %v", b.sleep)
+	}
+	for i := 0; i < 20; i++ {
+		b.wait(context.Background())
+	}
+	if b.sleep != backoffMaxSleep {
+		t.Fatalf("sleep: %v", b.sleep)
+	}
+
+	queue := NewMPMCQueue[int](4)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		queue.TryEnqueue(1)
+	}()
+	if el, err := queue.Dequeue(context.Background()); err != nil || el != 1 {
+		t.Fatalf("dequeue: %d, %v", el, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := queue.Dequeue(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+	if d := time.Since(start); d > 10*time.Millisecond+10*backoffMaxSleep {
+		t.Fatalf("dequeue took %v with a 10ms deadline", d)
+	}
+}
+
+func TestMPMCQueueConcurrent(t *testing.T) {
+	queue := NewMPMCQueue[int](64)
+	const producers, consumers, count = 4, 4, 5000
+	ctx := context.Background()
+
+	wg := new(sync.WaitGroup)
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				queue.Enqueue(ctx, p*count+i)
+			}
+		}(p)
+	}
+
+	received := make([][]int, consumers)
+	cwg := new(sync.WaitGroup)
+	for c := 0; c < consumers; c++ {
+		cwg.Add(1)
+		go func(c int) {
+			defer cwg.Done()
+			for i := 0; i < producers*count/consumers; i++ {
+				el, _ := queue.Dequeue(ctx)
+				received[c] = append(received[c], el)
+			}
+		}(c)
+	}
+	wg.Wait()
+	cwg.Wait()
+
+	seen := make([]bool, producers*count)
+	for _, l := range received {
+		last := make([]int, producers)
+		for i := range last {
+			last[i] = -1
+		}
+		for _, el := range l {
+			if seen[el] {
+				t.Fatalf("%d received twice", el)
+			}
+			seen[el] = true
+			// 同一消费者收到的同一生产者的元素保持 FIFO
+			if p := el / count; el <= last[p] {
+				t.Fatalf("%d received after %d", el, last[p])
+			} else {
+				last[p] = el
+			}
+		}
+	}
+	if queue.Len() != 0 {
+		t.Fatalf("len: %d", queue.Len())
+	}
+}
+
+// mutexQueue stack.Stack 式的互斥锁环形队列
+type mutexQueue struct {
+	buf     []int
+	head, n int
+	mux     sync.Mutex
+}
+
+func (q *mutexQueue) push(el int) bool {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	if q.n == len(q.buf) {
+		return false
+	}
+	q.buf[(q.head+q.n)%len(q.buf)] = el
+	q.n++
+	return true
+}
+
+func (q *mutexQueue) pop() bool {
+	q.mux.Lock()
+	defer q.mux.Unlock()
+	if q.n == 0 {
+		return false
+	}
+	q.head = (q.head + 1) % len(q.buf)
+	q.n--
+	return true
+}
+
+func BenchmarkMPMCQueue(b *testing.B) {
+	queue := NewMPMCQueue[int](1024)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			queue.TryEnqueue(1)
+			queue.TryDequeue()
+		}
+	})
+}
+
+func BenchmarkMutexQueue(b *testing.B) {
+	queue := &mutexQueue{buf: make([]int, 1024)}
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			queue.push(1)
+			queue.pop()
+		}
+	})
+}
+
+func BenchmarkChannel(b *testing.B) {
+	ch := make(chan int, 1024)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			select {
+			case ch <- 1:
+			default:
+			}
+			select {
+			case <-ch:
+			default:
+			}
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package queue
+
+import "github.com/hunyxv/datastructure/stack"
+
+// Queue FIFO queue based on a ring buffer
+type Queue[T any] struct {
+	deque *Deque[T]
+}
+
+// NewQueue create new bounded queue holding at most size elements
+func NewQueue[T any](size int) *Queue[T] {
+	return &Queue[T]{deque: NewDeque[T](size)}
+}
+
+// NewGrowableQueue create new unbounded queue with the initial capacity,
+// see NewGrowableDeque
+func NewGrowableQueue[T any](size int, policy stack.GrowthPolicy) *Queue[T] {
+	return &Queue[T]{deque: NewGrowableDeque[T](size, policy)}
+}
+
+// Push insert element at the back of the queue, a bounded queue returns
+// ErrFull if it is full
+func (q *Queue[T]) Push(ele T) error {
+	return q.deque.PushBack(ele)
+}
+
+// Pop delete and return the element at the front of the queue
+func (q *Queue[T]) Pop() (T, error) {
+	return q.deque.PopFront()
+}
+
+// Peek return the element at the front of the queue without removing it
+func (q *Queue[T]) Peek() (T, error) {
+	return q.deque.Front()
+}
+
+// Len returns the number of elements
+func (q *Queue[T]) Len() int {
+	return q.deque.Len()
+}
+
+// Cap returns the current capacity of the ring buffer
+func (q *Queue[T]) Cap() int {
+	return q.deque.Cap()
+}
+
+// IsEmpty return true if queue is empty
+func (q *Queue[T]) IsEmpty() bool {
+	return q.deque.IsEmpty()
+}
+
+// Clear remove all elements from the queue
+func (q *Queue[T]) Clear() {
+	q.deque.Clear()
+}
+
+// Traverse calls f sequentially for each element from the front to the back.
+// If f returns false, Traverse stops the iteration.
+func (q *Queue[T]) Traverse(f func(el T) bool) {
+	q.deque.Traverse(f)
+}
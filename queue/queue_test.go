@@ -0,0 +1,159 @@
+package queue
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/hunyxv/datastructure/stack"
+)
+
+func TestQueue(t *testing.T) {
+	queue := NewQueue[int](3)
+	for i := 0; i < 3; i++ {
+		if err := queue.Push(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := queue.Push(3); err != ErrFull {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+
+	// 出队后再入队，环形缓冲区回绕
+	for round := 0; round < 5; round++ {
+		el, err := queue.Pop()
+		if err != nil || el != round {
+			t.Fatalf("%d --> %d", round, el)
+		}
+		queue.Push(round + 3)
+	}
+	if front, _ := queue.Peek(); front != 5 || queue.Len() != 3 {
+		t.Fatalf("front: %d, len: %d", front, queue.Len())
+	}
+
+	var got []int
+	queue.Traverse(func(el int) bool {
+		got = append(got, el)
+		return true
+	})
+	for i, want := range []int{5, 6, 7} {
+		if got[i] != want {
+			t.Fatalf("%v", got)
+		}
+	}
+
+	queue.Clear()
+	if _, err := queue.Pop(); err != ErrEmpty || !queue.IsEmpty() {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+}
+
+func TestGrowableQueue(t *testing.T) {
+	queue := NewGrowableQueue[int](2, stack.DoublingShrink)
+	for i := 0; i < 100; i++ {
+		queue.Push(i)
+		if i%3 == 0 {
+			queue.Pop()
+		}
+	}
+	if queue.Len() != 66 || queue.Cap() != 128 {
+		t.Fatalf("len: %d, cap: %d", queue.Len(), queue.Cap())
+	}
+	for want := 34; !queue.IsEmpty(); want++ {
+		if el, _ := queue.Pop(); el != want {
+			t.Fatalf("%d --> %d", want, el)
+		}
+	}
+	if queue.Cap() != 2 {
+		t.Fatalf("cap should shrink back to 2, got %d", queue.Cap())
+	}
+}
+
+func TestDeque(t *testing.T) {
+	deque := NewDeque[int](4)
+	deque.PushBack(2)
+	deque.PushFront(1)
+	deque.PushBack(3)
+	deque.PushFront(0)
+	if err := deque.PushFront(-1); err != ErrFull {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if el, err := deque.At(i); err != nil || el != i {
+			t.Fatalf("at %d --> %d", i, el)
+		}
+	}
+	if _, err := deque.At(4); err != ErrExceed {
+		t.Fatalf("expected ErrExceed, got %v", err)
+	}
+
+	if el, _ := deque.PopBack(); el != 3 {
+		t.Fatalf("pop back: %d", el)
+	}
+	if el, _ := deque.PopFront(); el != 0 {
+		t.Fatalf("pop front: %d", el)
+	}
+	if front, _ := deque.Front(); front != 1 {
+		t.Fatalf("front: %d", front)
+	}
+	if back, _ := deque.Back(); back != 2 {
+		t.Fatalf("back: %d", back)
+	}
+	deque.PopBack()
+	deque.PopBack()
+	if _, err := deque.PopFront(); err != ErrEmpty {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+	if _, err := deque.At(0); err != ErrEmpty {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+}
+
+func TestGrowableDequeRandom(t *testing.T) {
+	deque := NewGrowableDeque[int](0, stack.DoublingShrink)
+	var ref []int
+	for i := 0; i < 10000; i++ {
+		switch rand.Intn(4) {
+		case 0:
+			deque.PushFront(i)
+			ref = append([]int{i}, ref...)
+		case 1:
+			deque.PushBack(i)
+			ref = append(ref, i)
+		case 2:
+			el, err := deque.PopFront()
+			if len(ref) == 0 {
+				if err != ErrEmpty {
+					t.Fatal("expected ErrEmpty")
+				}
+				continue
+			}
+			if el != ref[0] {
+				t.Fatalf("%d --> %d", ref[0], el)
+			}
+			ref = ref[1:]
+		case 3:
+			el, err := deque.PopBack()
+			if len(ref) == 0 {
+				if err != ErrEmpty {
+					t.Fatal("expected ErrEmpty")
+				}
+				continue
+			}
+			if el != ref[len(ref)-1] {
+				t.Fatalf("%d --> %d", ref[len(ref)-1], el)
+			}
+			ref = ref[:len(ref)-1]
+		}
+
+		if deque.Len() != len(ref) {
+			t.Fatalf("len: %d --> %d", len(ref), deque.Len())
+		}
+		if len(ref) > 0 {
+			k := rand.Intn(len(ref))
+			if el, _ := deque.At(k); el != ref[k] {
+				t.Fatalf("at %d: %d --> %d", k, ref[k], el)
+			}
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package stack
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrClosed stack is closed
+var ErrClosed = errors.New("stack is closed")
+
+// BlockingStack stack whose Pop blocks while it is empty and whose Push
+// blocks while it is full
+type BlockingStack[T any] struct {
+	stack    *Stack[T]
+	capacity int
+
+	closed   bool
+	done     chan struct{} // closed by Close to wake up all waiters
+	notEmpty chan struct{} // wakes up one Pop once the stack is not empty
+	notFull  chan struct{} // wakes up one Push once the stack is not full
+	mux      sync.Mutex
+}
+
+// NewBlockingStack create new blocking stack holding at most capacity
+// elements, the stack is unbounded if capacity <= 0
+func NewBlockingStack[T any](capacity int) *BlockingStack[T] {
+	s := &BlockingStack[T]{
+		capacity: capacity,
+		done:     make(chan struct{}),
+		notEmpty: make(chan struct{}, 1),
+		notFull:  make(chan struct{}, 1),
+	}
+	if capacity > 0 {
+		s.stack = NewStack[T](capacity)
+	} else {
+		s.stack = NewGrowableStack[T](0, DoublingShrink)
+	}
+	return s
+}
+
+// notify wakes up one waiter on c, the wakeup is kept for the next waiter
+// if nobody is waiting
+func notify(c chan struct{}) {
+	select {
+	case c <- struct{}{}:
+	default:
+	}
+}
+
+func (s *BlockingStack[T]) full() bool {
+	return s.capacity > 0 && s.stack.Len() >= s.capacity
+}
+
+// TryPush insert element into the top of the stack without blocking,
+// returns ErrFull if the stack is full and ErrClosed if it is closed
+func (s *BlockingStack[T]) TryPush(ele T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.tryPush(ele)
+}
+
+func (s *BlockingStack[T]) tryPush(ele T) error {
+	if s.closed {
+		return ErrClosed
+	}
+	if err := s.stack.Push(ele); err != nil {
+		return err
+	}
+	notify(s.notEmpty)
+	// wakeups of several Pops may have been merged into one, pass it on
+	// while there is still room
+	if !s.full() {
+		notify(s.notFull)
+	}
+	return nil
+}
+
+// Push insert element into the top of the stack, blocks while the stack is
+// full. It returns ErrClosed if the stack is closed and ctx.Err() if ctx is
+// done first.
+func (s *BlockingStack[T]) Push(ctx context.Context, ele T) error {
+	for {
+		s.mux.Lock()
+		err := s.tryPush(ele)
+		s.mux.Unlock()
+		if err != ErrFull {
+			return err
+		}
+
+		select {
+		case <-s.notFull:
+		case <-s.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// TryPop delete and return the element at the top of the stack without
+// blocking, returns ErrEmpty if the stack is empty, or ErrClosed if it is
+// also closed
+func (s *BlockingStack[T]) TryPop() (T, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.tryPop()
+}
+
+func (s *BlockingStack[T]) tryPop() (T, error) {
+	el, err := s.stack.Pop()
+	if err == nil {
+		notify(s.notFull)
+		// wakeups of several Pushes may have been merged into one, pass it
+		// on while there are still elements
+		if !s.stack.IsEmpty() {
+			notify(s.notEmpty)
+		}
+		return el, nil
+	}
+	if s.closed {
+		return el, ErrClosed
+	}
+	return el, err
+}
+
+// Pop delete and return the element at the top of the stack, blocks while
+// the stack is empty. Elements left in a closed stack can still be popped,
+// after that Pop returns ErrClosed. It returns ctx.Err() if ctx is done first.
+func (s *BlockingStack[T]) Pop(ctx context.Context) (T, error) {
+	for {
+		s.mux.Lock()
+		el, err := s.tryPop()
+		s.mux.Unlock()
+		if err != ErrEmpty {
+			return el, err
+		}
+
+		select {
+		case <-s.notEmpty:
+		case <-s.done:
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// Len returns the number of elements
+func (s *BlockingStack[T]) Len() int {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	return s.stack.Len()
+}
+
+// Close closes the stack and wakes up all waiters. Push fails with ErrClosed
+// afterwards, Pop returns the remaining elements and then ErrClosed.
+func (s *BlockingStack[T]) Close() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if s.closed {
+		return
+	}
+	s.closed = true
+	close(s.done)
+}
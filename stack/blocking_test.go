@@ -0,0 +1,127 @@
+package stack
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestBlockingStackPop(t *testing.T) {
+	stack := NewBlockingStack[int](0)
+	if _, err := stack.TryPop(); err != ErrEmpty {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+
+	result := make(chan int)
+	go func() {
+		el, err := stack.Pop(context.Background())
+		if err != nil {
+			t.Error(err)
+		}
+		result <- el
+	}()
+	time.Sleep(10 * time.Millisecond)
+	stack.Push(context.Background(), 7)
+	if el := <-result; el != 7 {
+		t.Fatalf("pop: %d", el)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := stack.Pop(ctx); err != context.DeadlineExceeded {
+		t.Fatalf("expected DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestBlockingStackPush(t *testing.T) {
+	stack := NewBlockingStack[int](2)
+	ctx := context.Background()
+	stack.TryPush(1)
+	stack.TryPush(2)
+	if err := stack.TryPush(3); err != ErrFull {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+
+	done := make(chan error)
+	go func() {
+		done <- stack.Push(ctx, 3)
+	}()
+	time.Sleep(10 * time.Millisecond)
+	if el, _ := stack.TryPop(); el != 2 {
+		t.Fatalf("pop: %d", el)
+	}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if el, _ := stack.Pop(ctx); el != 3 || stack.Len() != 1 {
+		t.Fatalf("pop: %d, len: %d", el, stack.Len())
+	}
+}
+
+func TestBlockingStackClose(t *testing.T) {
+	stack := NewBlockingStack[int](1)
+	stack.TryPush(1)
+
+	wg := new(sync.WaitGroup)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := stack.Push(context.Background(), 2); err != ErrClosed {
+			t.Errorf("expected ErrClosed, got %v", err)
+		}
+	}()
+	time.Sleep(10 * time.Millisecond)
+	stack.Close()
+	wg.Wait()
+
+	// 关闭后仍可取出剩余元素
+	if el, err := stack.Pop(context.Background()); err != nil || el != 1 {
+		t.Fatalf("pop: %d, %v", el, err)
+	}
+	if _, err := stack.Pop(context.Background()); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+	if err := stack.TryPush(1); err != ErrClosed {
+		t.Fatalf("expected ErrClosed, got %v", err)
+	}
+}
+
+// every blocked Pop and Push must be woken up in turn when only one waiter
+// is woken up per operation
+func TestBlockingStackHandoff(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(4))
+
+	stack := NewBlockingStack[int](1)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	const workers, n = 4, 200
+	wg := new(sync.WaitGroup)
+	for i := 0; i < workers; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				if err := stack.Push(ctx, j); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < n; j++ {
+				if _, err := stack.Pop(ctx); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if stack.Len() != 0 {
+		t.Fatalf("len: %d", stack.Len())
+	}
+}
@@ -0,0 +1,120 @@
+package stack
+
+import "sync"
+
+// MinMaxStack stack reporting its minimum and maximum element in O(1)
+type MinMaxStack[T any] struct {
+	stack *Stack[T]
+	mins  *Stack[T] // top is the current minimum
+	maxs  *Stack[T] // top is the current maximum
+	less  func(a, b T) bool
+	mux   sync.RWMutex
+}
+
+// NewMinMaxStack create new min/max-tracking stack ordered by less, holding
+// at most capacity elements. The stack is unbounded if capacity <= 0.
+func NewMinMaxStack[T any](capacity int, less func(a, b T) bool) *MinMaxStack[T] {
+	s := &MinMaxStack[T]{less: less}
+	if capacity > 0 {
+		s.stack = NewStack[T](capacity)
+		s.mins = NewStack[T](capacity)
+		s.maxs = NewStack[T](capacity)
+	} else {
+		s.stack = NewGrowableStack[T](0, DoublingShrink)
+		s.mins = NewGrowableStack[T](0, DoublingShrink)
+		s.maxs = NewGrowableStack[T](0, DoublingShrink)
+	}
+	return s
+}
+
+// Push insert element into the top of the stack, a bounded stack
+// returns ErrFull if it is full
+func (s *MinMaxStack[T]) Push(ele T) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	if err := s.stack.Push(ele); err != nil {
+		return err
+	}
+	// equal elements are pushed too, so that popping one of them
+	// leaves the other as the extremum
+	if min, err := s.mins.Peek(); err != nil || !s.less(min, ele) {
+		s.mins.Push(ele)
+	}
+	if max, err := s.maxs.Peek(); err != nil || !s.less(ele, max) {
+		s.maxs.Push(ele)
+	}
+	return nil
+}
+
+// Pop delete and return the element at the top of the stack
+func (s *MinMaxStack[T]) Pop() (T, error) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	el, err := s.stack.Pop()
+	if err != nil {
+		return el, err
+	}
+	// the element is the extremum iff it compares equal to the top
+	if min, _ := s.mins.Peek(); !s.less(min, el) {
+		s.mins.Pop()
+	}
+	if max, _ := s.maxs.Peek(); !s.less(el, max) {
+		s.maxs.Pop()
+	}
+	return el, nil
+}
+
+// Peek return the top element of the stack if
+// the stack is not empty
+func (s *MinMaxStack[T]) Peek() (T, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.stack.Peek()
+}
+
+// Min return the minimum element of the stack if
+// the stack is not empty
+func (s *MinMaxStack[T]) Min() (T, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.mins.Peek()
+}
+
+// Max return the maximum element of the stack if
+// the stack is not empty
+func (s *MinMaxStack[T]) Max() (T, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.maxs.Peek()
+}
+
+// Len returns the number of elements
+func (s *MinMaxStack[T]) Len() int {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.stack.Len()
+}
+
+// IsEmpty return true if stack is empty
+func (s *MinMaxStack[T]) IsEmpty() bool {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return s.stack.IsEmpty()
+}
+
+// Clear remove all elements from the stack
+func (s *MinMaxStack[T]) Clear() {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+	s.stack.Clear()
+	s.mins.Clear()
+	s.maxs.Clear()
+}
+
+// StackTraverse calls f sequentially for element present in the stack from
+// bottom to top. If f returns false, StackTraverse stops the iteration.
+func (s *MinMaxStack[T]) StackTraverse(f func(el T) bool) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	s.stack.StackTraverse(f)
+}
@@ -0,0 +1,74 @@
+package stack
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestMinMaxStack(t *testing.T) {
+	s := NewMinMaxStack(0, func(a, b int) bool { return a < b })
+	if _, err := s.Min(); err != ErrEmpty {
+		t.Fatalf("Min() on empty stack = %v, want ErrEmpty", err)
+	}
+
+	// 与逐个扫描的结果对比
+	var ref []int
+	for i := 0; i < 2000; i++ {
+		if len(ref) == 0 || rand.Intn(3) > 0 {
+			v := rand.Intn(50)
+			if err := s.Push(v); err != nil {
+				t.Fatal(err)
+			}
+			ref = append(ref, v)
+		} else {
+			v, err := s.Pop()
+			if err != nil || v != ref[len(ref)-1] {
+				t.Fatalf("Pop() = %d, %v, want %d", v, err, ref[len(ref)-1])
+			}
+			ref = ref[:len(ref)-1]
+		}
+		if len(ref) == 0 {
+			continue
+		}
+		min, max := ref[0], ref[0]
+		for _, v := range ref {
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+		gotMin, _ := s.Min()
+		gotMax, _ := s.Max()
+		if gotMin != min || gotMax != max {
+			t.Fatalf("Min(), Max() = %d, %d, want %d, %d", gotMin, gotMax, min, max)
+		}
+		if s.Len() != len(ref) {
+			t.Fatalf("Len() = %d, want %d", s.Len(), len(ref))
+		}
+	}
+}
+
+func TestMinMaxStackBounded(t *testing.T) {
+	s := NewMinMaxStack(2, func(a, b string) bool { return a < b })
+	s.Push("b")
+	s.Push("a")
+	if err := s.Push("c"); err != ErrFull {
+		t.Fatalf("Push() on full stack = %v, want ErrFull", err)
+	}
+	if max, _ := s.Max(); max != "b" {
+		t.Fatalf("Max() = %q, want b", max)
+	}
+	s.Clear()
+	if !s.IsEmpty() {
+		t.Fatal("stack should be empty after Clear")
+	}
+	if _, err := s.Max(); err != ErrEmpty {
+		t.Fatalf("Max() after Clear = %v, want ErrEmpty", err)
+	}
+	s.Push("c")
+	if min, _ := s.Min(); min != "c" || s.Len() != 1 {
+		t.Fatalf("Min() = %q, len %d after clear-then-push", min, s.Len())
+	}
+}
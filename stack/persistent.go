@@ -0,0 +1,94 @@
+package stack
+
+// PersistentStack immutable stack, Push and Pop return a new version sharing
+// all existing elements with the old one, so both remain valid and keeping
+// many versions costs O(1) space per Push. The zero value is an empty stack.
+//
+// A PersistentStack is safe for concurrent use as long as the elements
+// themselves are not modified.
+type PersistentStack[T any] struct {
+	top *treiberNode[T]
+}
+
+// NewPersistentStack create new persistent stack holding elements, the last
+// element is at the top
+func NewPersistentStack[T any](elements ...T) PersistentStack[T] {
+	var s PersistentStack[T]
+	for _, ele := range elements {
+		s = s.Push(ele)
+	}
+	return s
+}
+
+// Push returns a new version of the stack with element on the top
+func (s PersistentStack[T]) Push(ele T) PersistentStack[T] {
+	n := &treiberNode[T]{val: ele, next: s.top, n: 1}
+	if s.top != nil {
+		n.n = s.top.n + 1
+	}
+	return PersistentStack[T]{top: n}
+}
+
+// Pop returns the element at the top and a new version of the stack
+// without it, s itself is left unchanged
+func (s PersistentStack[T]) Pop() (T, PersistentStack[T], error) {
+	if s.top == nil {
+		var zero T
+		return zero, s, ErrEmpty
+	}
+	return s.top.val, PersistentStack[T]{top: s.top.next}, nil
+}
+
+// Peek return the top element of the stack if
+// the stack is not empty
+func (s PersistentStack[T]) Peek() (T, error) {
+	if s.top == nil {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return s.top.val, nil
+}
+
+// Len returns the number of elements
+func (s PersistentStack[T]) Len() int {
+	if s.top == nil {
+		return 0
+	}
+	return s.top.n
+}
+
+// IsEmpty return true if stack is empty
+func (s PersistentStack[T]) IsEmpty() bool {
+	return s.top == nil
+}
+
+// TraverseFromTop calls f sequentially for element present in the stack from
+// top to bottom. If f returns false, TraverseFromTop stops the iteration.
+func (s PersistentStack[T]) TraverseFromTop(f func(el T) bool) {
+	for n := s.top; n != nil; n = n.next {
+		if !f(n.val) {
+			break
+		}
+	}
+}
+
+// StackTraverse calls f sequentially for element present in the stack from
+// bottom to top. If f returns false, StackTraverse stops the iteration.
+func (s PersistentStack[T]) StackTraverse(f func(el T) bool) {
+	for _, el := range s.Snapshot() {
+		if !f(el) {
+			break
+		}
+	}
+}
+
+// Snapshot returns a copy of the elements ordered from bottom to top
+func (s PersistentStack[T]) Snapshot() []T {
+	snapshot := make([]T, s.Len())
+	i := len(snapshot)
+	for n := s.top; n != nil; n = n.next {
+		i--
+		snapshot[i] = n.val
+	}
+	return snapshot
+}
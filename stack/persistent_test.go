@@ -0,0 +1,84 @@
+package stack
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPersistentStack(t *testing.T) {
+	var empty PersistentStack[int]
+	if _, _, err := empty.Pop(); err != ErrEmpty {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+
+	v1 := NewPersistentStack(1, 2, 3)
+	v2 := v1.Push(4)
+	el, v3, err := v1.Pop()
+	if err != nil || el != 3 {
+		t.Fatalf("Pop() = %d, %v", el, err)
+	}
+	v4 := v3.Push(5)
+
+	// 旧版本保持不变
+	for _, c := range []struct {
+		s    PersistentStack[int]
+		want []int
+	}{
+		{empty, []int{}},
+		{v1, []int{1, 2, 3}},
+		{v2, []int{1, 2, 3, 4}},
+		{v3, []int{1, 2}},
+		{v4, []int{1, 2, 5}},
+	} {
+		if got := c.s.Snapshot(); !reflect.DeepEqual(got, c.want) {
+			t.Fatalf("snapshot %v, want %v", got, c.want)
+		}
+		if c.s.Len() != len(c.want) || c.s.IsEmpty() != (len(c.want) == 0) {
+			t.Fatalf("len %d, want %d", c.s.Len(), len(c.want))
+		}
+		if top, err := c.s.Peek(); len(c.want) > 0 && (err != nil || top != c.want[len(c.want)-1]) {
+			t.Fatalf("Peek() = %d, %v, want %d", top, err, c.want[len(c.want)-1])
+		}
+	}
+
+	// 共享结构
+	if v3.top != v1.top.next || v4.top.next != v3.top {
+		t.Fatal("versions should share nodes")
+	}
+}
+
+func TestPersistentStackTraverse(t *testing.T) {
+	s := NewPersistentStack("a", "b", "c")
+
+	var top, bottom []string
+	s.TraverseFromTop(func(el string) bool {
+		top = append(top, el)
+		return el != "b"
+	})
+	s.StackTraverse(func(el string) bool {
+		bottom = append(bottom, el)
+		return true
+	})
+	if !reflect.DeepEqual(top, []string{"c", "b"}) || !reflect.DeepEqual(bottom, []string{"a", "b", "c"}) {
+		t.Fatalf("from top: %v, from bottom: %v", top, bottom)
+	}
+}
+
+// 使用持久化栈实现撤销
+func TestPersistentStackUndo(t *testing.T) {
+	var history []PersistentStack[string]
+	var doc PersistentStack[string]
+	for _, op := range []string{"type", "bold", "delete"} {
+		history = append(history, doc)
+		doc = doc.Push(op)
+	}
+
+	// 撤销两次
+	doc = history[len(history)-2]
+	if got := doc.Snapshot(); !reflect.DeepEqual(got, []string{"type"}) {
+		t.Fatalf("after undo: %v", got)
+	}
+	if got := history[2].Push("italic").Snapshot(); !reflect.DeepEqual(got, []string{"type", "bold", "italic"}) {
+		t.Fatalf("branch: %v", got)
+	}
+}
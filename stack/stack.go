@@ -12,96 +12,182 @@ var (
 	ErrFull = errors.New("stack is full")
 )
 
+// GrowthPolicy growth policy of a growable stack
+type GrowthPolicy interface {
+	// Grow returns the new capacity when the stack is full
+	Grow(capacity int) int
+	// Shrink returns the new capacity after a pop, or capacity
+	// itself if the stack should not shrink
+	Shrink(length, capacity int) int
+}
+
+type doubling struct {
+	shrink bool
+}
+
+func (d doubling) Grow(capacity int) int {
+	if capacity == 0 {
+		return 1
+	}
+	return capacity * 2
+}
+
+func (d doubling) Shrink(length, capacity int) int {
+	if d.shrink && length <= capacity/4 {
+		return capacity / 2
+	}
+	return capacity
+}
+
+var (
+	// Doubling doubles the capacity when the stack is full and never shrinks
+	Doubling GrowthPolicy = doubling{}
+	// DoublingShrink doubles the capacity when the stack is full and halves
+	// it when no more than a quarter of it is used
+	DoublingShrink GrowthPolicy = doubling{shrink: true}
+)
+
 // Stack stack
-type Stack struct {
-	stack []interface{}
-	base  int
-	top   int
-	size  int
-	mux   *sync.RWMutex
-}
-
-// NewStack create new stack
-func NewStack(size int) *Stack {
-	return &Stack{
-		stack: make([]interface{}, size),
+type Stack[T any] struct {
+	stack  []T
+	base   int
+	top    int
+	size   int          // capacity of a bounded stack, initial capacity of a growable one
+	growth GrowthPolicy // nil for a bounded stack
+	mux    *sync.RWMutex
+}
+
+// NewStack create new bounded stack holding at most size elements
+func NewStack[T any](size int) *Stack[T] {
+	return &Stack[T]{
+		stack: make([]T, size),
 		size:  size,
 		top:   -1,
 		mux:   new(sync.RWMutex),
 	}
 }
 
-// ClearStack clear the stack
-func (s *Stack) ClearStack() {
+// NewGrowableStack create new unbounded stack with the initial capacity,
+// the backing slice grows (and optionally shrinks, never below the initial
+// capacity) according to policy. Doubling is used if policy is nil.
+func NewGrowableStack[T any](size int, policy GrowthPolicy) *Stack[T] {
+	if policy == nil {
+		policy = Doubling
+	}
+	s := NewStack[T](size)
+	s.growth = policy
+	return s
+}
+
+// Clear remove all elements from the stack, resetting its length to 0 and
+// its capacity to the initial one
+func (s *Stack[T]) Clear() {
 	s.mux.Lock()
 	defer s.mux.Unlock()
-	s.stack = make([]interface{}, s.size)
+	s.stack = make([]T, s.size)
+	s.top = -1
+}
+
+// ClearStack is the same as Clear
+func (s *Stack[T]) ClearStack() {
+	s.Clear()
 }
 
 // IsEmpty return true if stack is empty
-func (s *Stack) IsEmpty() bool {
+func (s *Stack[T]) IsEmpty() bool {
 	s.mux.RLock()
 	defer s.mux.RUnlock()
 	return s.top == -1
 }
 
-// StackLength returns the number of elements
-func (s *Stack) StackLength() int {
+// Len returns the number of elements
+func (s *Stack[T]) Len() int {
 	s.mux.RLock()
 	defer s.mux.RUnlock()
 	return s.top + 1
 }
 
-// GetTop return the top element of the stack if
+// StackLength is the same as Len
+func (s *Stack[T]) StackLength() int {
+	return s.Len()
+}
+
+// Cap returns the current capacity of the stack
+func (s *Stack[T]) Cap() int {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	return len(s.stack)
+}
+
+// Peek return the top element of the stack if
 // the stack is not empty
-func (s *Stack) GetTop() (interface{}, error) {
-	if s.IsEmpty() {
-		return nil, ErrEmpty
-	}
+func (s *Stack[T]) Peek() (T, error) {
 	s.mux.RLock()
 	defer s.mux.RUnlock()
 	if s.top == -1 {
-		return nil, ErrEmpty
+		var zero T
+		return zero, ErrEmpty
 	}
 	return s.stack[s.top], nil
 }
 
-// Push insert element into the top of the stack
-func (s *Stack) Push(ele interface{}) error {
-	if s.top == s.size {
-		return ErrFull
-	}
+// GetTop is the same as Peek
+func (s *Stack[T]) GetTop() (T, error) {
+	return s.Peek()
+}
 
+// Push insert element into the top of the stack, a bounded stack
+// returns ErrFull if it is full
+func (s *Stack[T]) Push(ele T) error {
 	s.mux.Lock()
 	defer s.mux.Unlock()
-	if s.top == s.size {
-		return ErrFull
+	if s.top+1 == len(s.stack) {
+		if s.growth == nil {
+			return ErrFull
+		}
+		s.resize(s.growth.Grow(len(s.stack)))
 	}
 	s.top++
 	s.stack[s.top] = ele
 	return nil
 }
 
-// Pop delete and return the element at the top of the stack
-func (s *Stack) Pop() (interface{}, error) {
-	if s.IsEmpty() {
-		return nil, ErrEmpty
+// resize reallocates the backing slice with the capacity
+func (s *Stack[T]) resize(capacity int) {
+	if capacity <= s.top {
+		capacity = s.top + 2
 	}
+	stack := make([]T, capacity)
+	copy(stack, s.stack[:s.top+1])
+	s.stack = stack
+}
 
+// Pop delete and return the element at the top of the stack
+func (s *Stack[T]) Pop() (T, error) {
 	s.mux.Lock()
 	defer s.mux.Unlock()
+	var zero T
 	if s.top == -1 {
-		return nil, ErrEmpty
+		return zero, ErrEmpty
 	}
 	el := s.stack[s.top]
-	s.stack[s.top] = nil
+	s.stack[s.top] = zero
 	s.top--
+	if s.growth != nil {
+		capacity := s.growth.Shrink(s.top+1, len(s.stack))
+		if capacity < s.size {
+			capacity = s.size
+		}
+		if capacity < len(s.stack) {
+			s.resize(capacity)
+		}
+	}
 	return el, nil
 }
 
-// StackTraverse calls f sequentially for element present in the stack. If f
-// returns false, StackTraverse stops the iteration.
-func (s *Stack) StackTraverse(f func(el interface{}) bool) {
+// StackTraverse calls f sequentially for element present in the stack from
+// bottom to top. If f returns false, StackTraverse stops the iteration.
+func (s *Stack[T]) StackTraverse(f func(el T) bool) {
 	s.mux.RLock()
 	defer s.mux.RUnlock()
 	for i := 0; i <= s.top; i++ {
@@ -110,3 +196,25 @@ func (s *Stack) StackTraverse(f func(el interface{}) bool) {
 		}
 	}
 }
+
+// TraverseFromTop calls f sequentially for element present in the stack from
+// top to bottom. If f returns false, TraverseFromTop stops the iteration.
+func (s *Stack[T]) TraverseFromTop(f func(el T) bool) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	for i := s.top; i >= 0; i-- {
+		if !f(s.stack[i]) {
+			break
+		}
+	}
+}
+
+// Snapshot returns a copy of the elements ordered from bottom to top, taken
+// atomically with respect to concurrent Push and Pop
+func (s *Stack[T]) Snapshot() []T {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+	snapshot := make([]T, s.top+1)
+	copy(snapshot, s.stack[:s.top+1])
+	return snapshot
+}
@@ -5,7 +5,7 @@ import (
 )
 
 func TestStackPushPop(t *testing.T) {
-	stack := NewStack(10)
+	stack := NewStack[int](10)
 	for i := 0; i < 10; i++ {
 		if err := stack.Push(i); err != nil {
 			t.Fatal(err)
@@ -22,16 +22,90 @@ func TestStackPushPop(t *testing.T) {
 	}
 }
 
+func TestStackPeekClear(t *testing.T) {
+	stack := NewStack[string](4)
+	if _, err := stack.Peek(); err != ErrEmpty {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+	stack.Push("a")
+	stack.Push("b")
+	if top, _ := stack.Peek(); top != "b" || stack.Len() != 2 {
+		t.Fatalf("top: %s, len: %d", top, stack.Len())
+	}
+
+	stack.Clear()
+	if !stack.IsEmpty() || stack.Len() != 0 {
+		t.Fatal("stack should be empty")
+	}
+	if el, err := stack.Pop(); err != ErrEmpty || el != "" {
+		t.Fatalf("expected ErrEmpty, got %q, %v", el, err)
+	}
+}
+
+func TestBoundedStackFull(t *testing.T) {
+	stack := NewStack[int](3)
+	for i := 0; i < 3; i++ {
+		if err := stack.Push(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := stack.Push(3); err != ErrFull {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+	if stack.StackLength() != 3 || stack.Cap() != 3 {
+		t.Fatalf("length: %d, cap: %d", stack.StackLength(), stack.Cap())
+	}
+
+	if err := NewStack[int](0).Push(1); err != ErrFull {
+		t.Fatalf("expected ErrFull, got %v", err)
+	}
+}
+
+func TestGrowableStack(t *testing.T) {
+	stack := NewGrowableStack[int](2, DoublingShrink)
+	for i := 0; i < 100; i++ {
+		if err := stack.Push(i); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if stack.StackLength() != 100 || stack.Cap() != 128 {
+		t.Fatalf("length: %d, cap: %d", stack.StackLength(), stack.Cap())
+	}
+
+	for i := 99; i >= 0; i-- {
+		el, err := stack.Pop()
+		if err != nil || el != i {
+			t.Fatalf("%d --> %v", i, el)
+		}
+		if stack.Cap() < 2 || stack.Cap() < stack.StackLength() {
+			t.Fatalf("length: %d, cap: %d", stack.StackLength(), stack.Cap())
+		}
+	}
+	if stack.Cap() != 2 {
+		t.Fatalf("cap should shrink back to 2, got %d", stack.Cap())
+	}
+
+	stack = NewGrowableStack[int](0, nil)
+	for i := 0; i < 10; i++ {
+		stack.Push(i)
+	}
+	for i := 0; i < 10; i++ {
+		stack.Pop()
+	}
+	if stack.Cap() != 16 {
+		t.Fatalf("Doubling should not shrink, cap: %d", stack.Cap())
+	}
+}
+
 func TestStackTraverse(t *testing.T) {
-	stack := NewStack(10)
+	stack := NewStack[int](10)
 	for i := 0; i < 10; i++ {
 		if err := stack.Push(i); err != nil {
 			t.Fatal(err)
 		}
 	}
 
-	stack.StackTraverse(func(el interface{}) bool {
-		i := el.(int)
+	stack.StackTraverse(func(i int) bool {
 		if i > 7 {
 			return false
 		}
@@ -39,6 +113,76 @@ func TestStackTraverse(t *testing.T) {
 	})
 }
 
+func TestTraverseFromTop(t *testing.T) {
+	stack := NewGrowableStack[int](0, nil)
+	for i := 0; i < 10; i++ {
+		stack.Push(i)
+	}
+
+	var got []int
+	stack.TraverseFromTop(func(i int) bool {
+		got = append(got, i)
+		return i > 5
+	})
+	if len(got) != 5 || got[0] != 9 || got[4] != 5 {
+		t.Fatalf("TraverseFromTop visited %v, want 9..5", got)
+	}
+}
+
+func TestStackSnapshot(t *testing.T) {
+	stack := NewStack[int](4)
+	if snapshot := stack.Snapshot(); len(snapshot) != 0 {
+		t.Fatalf("snapshot of empty stack: %v", snapshot)
+	}
+	stack.Push(1)
+	stack.Push(2)
+	snapshot := stack.Snapshot()
+	if len(snapshot) != 2 || snapshot[0] != 1 || snapshot[1] != 2 {
+		t.Fatalf("snapshot: %v", snapshot)
+	}
+
+	// 快照与栈互不影响
+	snapshot[0] = 100
+	stack.Pop()
+	stack.Push(3)
+	if snapshot[1] != 2 {
+		t.Fatalf("snapshot changed by Push: %v", snapshot)
+	}
+	if el := stack.Snapshot(); el[0] != 1 || el[1] != 3 {
+		t.Fatalf("stack changed by snapshot: %v", el)
+	}
+}
+
+func TestClearStackThenPush(t *testing.T) {
+	for name, stack := range map[string]*Stack[int]{
+		"bounded":  NewStack[int](3),
+		"growable": NewGrowableStack[int](1, DoublingShrink),
+	} {
+		for round := 0; round < 3; round++ {
+			for i := 0; i < 3; i++ {
+				if err := stack.Push(i); err != nil {
+					t.Fatalf("%s: round %d: %v", name, round, err)
+				}
+			}
+			stack.ClearStack()
+			if !stack.IsEmpty() || stack.StackLength() != 0 || len(stack.Snapshot()) != 0 {
+				t.Fatalf("%s: stack should be empty after ClearStack", name)
+			}
+			if el, err := stack.Pop(); err != ErrEmpty || el != 0 {
+				t.Fatalf("%s: expected ErrEmpty, got %d, %v", name, el, err)
+			}
+		}
+
+		stack.Push(42)
+		if top, err := stack.GetTop(); err != nil || top != 42 || stack.Len() != 1 {
+			t.Fatalf("%s: top %d, len %d, err %v after clear-then-push", name, top, stack.Len(), err)
+		}
+		if el, _ := stack.Pop(); el != 42 || !stack.IsEmpty() {
+			t.Fatalf("%s: Pop() = %d, stack empty: %v", name, el, stack.IsEmpty())
+		}
+	}
+}
+
 // 使用 stack 来迷宫求解
 // 迷宫范围 1,1 --> 8,8
 var maze = [10][10]int{
@@ -80,7 +224,7 @@ func (p *point) next() *point {
 }
 
 func TestMaze(t *testing.T) {
-	stack := NewStack(64)
+	stack := NewStack[*point](64)
 	var count int
 	p := &point{x: 1, y: 1}
 
@@ -96,11 +240,10 @@ func TestMaze(t *testing.T) {
 			if top, err := stack.Pop(); err != nil {
 				break
 			} else {
-				t := top.(*point)
-				maze[t.x][t.y] = 0
+				maze[top.x][top.y] = 0
 			}
 			if top, err := stack.GetTop(); err == nil {
-				p = top.(*point)
+				p = top
 			}
 			continue
 		}
@@ -111,12 +254,11 @@ func TestMaze(t *testing.T) {
 			if top, err := stack.Pop(); err != nil {
 				break
 			} else {
-				t := top.(*point)
-				maze[t.x][t.y] = 0
+				maze[top.x][top.y] = 0
 			}
 
 			if top, err := stack.GetTop(); err == nil {
-				p = top.(*point)
+				p = top
 			}
 		}
 	}
@@ -0,0 +1,87 @@
+package stack
+
+import (
+	"sync/atomic"
+	"unsafe"
+)
+
+// treiberNode is an immutable node of TreiberStack and PersistentStack, n is
+// the number of elements from this node down to the bottom
+type treiberNode[T any] struct {
+	val  T
+	next *treiberNode[T]
+	n    int
+}
+
+// TreiberStack lock-free stack based on compare-and-swap of the top pointer.
+//
+// Every Push allocates a new node and nodes are never reused, so as long as a
+// goroutine holds a node the garbage collector cannot recycle its address.
+// This rules out the ABA problem without tagged pointers or hazard pointers.
+// The zero value is an empty stack ready to use.
+type TreiberStack[T any] struct {
+	head unsafe.Pointer // *treiberNode[T]
+}
+
+// NewTreiberStack create new lock-free stack
+func NewTreiberStack[T any]() *TreiberStack[T] {
+	return &TreiberStack[T]{}
+}
+
+func (s *TreiberStack[T]) load() *treiberNode[T] {
+	return (*treiberNode[T])(atomic.LoadPointer(&s.head))
+}
+
+// Push insert element into the top of the stack
+func (s *TreiberStack[T]) Push(ele T) {
+	n := &treiberNode[T]{val: ele}
+	for {
+		top := s.load()
+		n.next, n.n = top, 1
+		if top != nil {
+			n.n = top.n + 1
+		}
+		if atomic.CompareAndSwapPointer(&s.head, unsafe.Pointer(top), unsafe.Pointer(n)) {
+			return
+		}
+	}
+}
+
+// Pop delete and return the element at the top of the stack
+func (s *TreiberStack[T]) Pop() (T, error) {
+	for {
+		top := s.load()
+		if top == nil {
+			var zero T
+			return zero, ErrEmpty
+		}
+		if atomic.CompareAndSwapPointer(&s.head, unsafe.Pointer(top), unsafe.Pointer(top.next)) {
+			return top.val, nil
+		}
+	}
+}
+
+// Peek return the top element of the stack if
+// the stack is not empty
+func (s *TreiberStack[T]) Peek() (T, error) {
+	top := s.load()
+	if top == nil {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return top.val, nil
+}
+
+// Len returns the number of elements at the moment of the call
+func (s *TreiberStack[T]) Len() int {
+	top := s.load()
+	if top == nil {
+		return 0
+	}
+	return top.n
+}
+
+// IsEmpty return true if stack is empty
+func (s *TreiberStack[T]) IsEmpty() bool {
+	return s.load() == nil
+}
@@ -0,0 +1,97 @@
+package stack
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestTreiberStack(t *testing.T) {
+	var stack TreiberStack[int]
+	if _, err := stack.Pop(); err != ErrEmpty {
+		t.Fatalf("expected ErrEmpty, got %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		stack.Push(i)
+	}
+	if top, _ := stack.Peek(); top != 9 || stack.Len() != 10 {
+		t.Fatalf("top: %d, len: %d", top, stack.Len())
+	}
+	for i := 9; i >= 0; i-- {
+		if el, err := stack.Pop(); err != nil || el != i {
+			t.Fatalf("%d --> %d", i, el)
+		}
+	}
+	if !stack.IsEmpty() || stack.Len() != 0 {
+		t.Fatal("stack should be empty")
+	}
+}
+
+func TestTreiberStackConcurrent(t *testing.T) {
+	stack := NewTreiberStack[int]()
+	const workers, count = 8, 2000
+
+	var popped sync.Map
+	wg := new(sync.WaitGroup)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < count; i++ {
+				stack.Push(w*count + i)
+				// 交替出栈以制造竞争
+				if i%2 == 1 {
+					el, err := stack.Pop()
+					if err != nil {
+						t.Error(err)
+						return
+					}
+					if _, dup := popped.LoadOrStore(el, struct{}{}); dup {
+						t.Errorf("%d popped twice", el)
+					}
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if stack.Len() != workers*count/2 {
+		t.Fatalf("len: %d", stack.Len())
+	}
+	for {
+		el, err := stack.Pop()
+		if err != nil {
+			break
+		}
+		if _, dup := popped.LoadOrStore(el, struct{}{}); dup {
+			t.Fatalf("%d popped twice", el)
+		}
+	}
+	n := 0
+	popped.Range(func(_, _ any) bool {
+		n++
+		return true
+	})
+	if n != workers*count {
+		t.Fatalf("popped %d elements", n)
+	}
+}
+
+func BenchmarkTreiberStack(b *testing.B) {
+	stack := NewTreiberStack[int]()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			stack.Push(1)
+			stack.Pop()
+		}
+	})
+}
+
+func BenchmarkMutexStack(b *testing.B) {
+	stack := NewGrowableStack[int](64, nil)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			stack.Push(1)
+			stack.Pop()
+		}
+	})
+}
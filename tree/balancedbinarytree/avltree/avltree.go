@@ -196,15 +196,14 @@ func (t *AVLTree) Depth() int {
 // Traversal 遍历各个值（深度优先--中序遍历 (从小到大)）
 func (t *AVLTree) Traversal(f func(Interface) bool) {
 	current := t
-	sk := stack.NewStack(int(t.Depth()))
+	sk := stack.NewGrowableStack[*AVLTree](int(t.Depth())+1, nil)
 	for current != nil || !sk.IsEmpty() {
 		if current != nil {
 			sk.Push(current)
 			current = current.lsubtree
 			continue
 		}
-		el, _ := sk.Pop()
-		node := el.(*AVLTree)
+		node, _ := sk.Pop()
 		if !f(node.data) {
 			return
 		}
@@ -155,15 +155,14 @@ func (t *BSTNode) Data() Interface {
 // Traversal 遍历各个值（深度优先--中序遍历 (从小到大)）
 func (t *BSTNode) Traversal(f func(Interface) bool) {
 	current := t
-	sk := stack.NewStack(int(t.Depth()))
+	sk := stack.NewGrowableStack[*BSTNode](int(t.Depth())+1, nil)
 	for current != nil || !sk.IsEmpty() {
 		if current != nil {
 			sk.Push(current)
 			current = current.lsubtree
 			continue
 		}
-		el, _ := sk.Pop()
-		node := el.(*BSTNode)
+		node, _ := sk.Pop()
 		if !f(node.data) {
 			return
 		}
@@ -50,7 +50,8 @@ func (n *HuffmanNode) Value() float64 {
 	return n.w
 }
 
-func (n *HuffmanNode) Key() any { return nil }
+// Key 以节点自身作为唯一标识
+func (n *HuffmanNode) Key() any { return n }
 
 // IsLeaf 是否为叶子节点
 func (n *HuffmanNode) IsLeaf() bool {
@@ -105,7 +106,8 @@ func (n *HuffmanLeafNode) Value() float64 {
 	return n.w
 }
 
-func (n *HuffmanLeafNode) Key() any { return nil }
+// Key 以节点自身作为唯一标识
+func (n *HuffmanLeafNode) Key() any { return n }
 
 // IsLeaf 是否为叶子节点
 func (n *HuffmanLeafNode) IsLeaf() bool {
@@ -141,13 +143,17 @@ func (n *HuffmanLeafNode) TraversalLeaf(f func(Leaf)) {
 
 // CreateHuffmanTree 创建赫夫曼树
 func CreateHuffmanTree(elements ...Element) Node {
+	return CreateHuffmanTreeWith(heap.NewBinaryHeap(heap.MinHeap), elements...)
+}
+
+// CreateHuffmanTreeWith 使用指定的优先队列创建赫夫曼树
+//	eleHeap 必须是空的最小堆
+func CreateHuffmanTreeWith(eleHeap heap.PriorityQueue, elements ...Element) Node {
 	if len(elements) == 0 {
 		return nil
 	}
 
 	var node Node
-	// 最小堆
-	eleHeap := heap.NewBinaryHeap(heap.MinHeap)
 	for _, ele := range elements {
 		node = newHuffmanLeafNode(ele.Weight(), ele.Char())
 		eleHeap.Insert(node)
@@ -6,9 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math/bits"
 	"os"
+	"path/filepath"
 	"syscall"
 	"testing"
+
+	"github.com/hunyxv/datastructure/heap"
 )
 
 func print01(code uint64) {
@@ -186,8 +190,8 @@ func TestBMPCompress(t *testing.T) {
 		table[pixel] = uint64TO01Code(leaf.Code()) // 像素和赫夫曼编码对应关系
 	})
 
-	// 压缩文件
-	compressedFile, err := os.OpenFile("testbmp/bmp_compress.huf", syscall.O_CREAT|syscall.O_WRONLY|syscall.O_TRUNC, 0666)
+	// 压缩文件（写入临时目录，不改动 testbmp 下的文件）
+	compressedFile, err := os.OpenFile(filepath.Join(t.TempDir(), "bmp_compress.huf"), syscall.O_CREAT|syscall.O_WRONLY|syscall.O_TRUNC, 0666)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -235,15 +239,15 @@ func TestBMPCompress(t *testing.T) {
 
 // 解压缩
 func TestBMPDecompress(t *testing.T) {
-	// 压缩文件
+	// 压缩文件（testbmp 下提交的样例）
 	hufFile, err := os.Open("testbmp/bmp_compress.huf")
 	if err != nil {
 		t.Fatal(err)
 	}
 	hufBuf := bufio.NewReader(hufFile)
 
-	// 解压缩文件
-	bmpFile, err := os.OpenFile("testbmp/bmp_decompress.bmp", syscall.O_CREAT|syscall.O_WRONLY|syscall.O_TRUNC, 0666)
+	// 解压缩文件（写入临时目录）
+	bmpFile, err := os.OpenFile(filepath.Join(t.TempDir(), "bmp_decompress.bmp"), syscall.O_CREAT|syscall.O_WRONLY|syscall.O_TRUNC, 0666)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -295,3 +299,40 @@ func TestBMPDecompress(t *testing.T) {
 		bmpBuf.Write(uint64TOChar(table[b], int(dibHeader.PixelSize)))
 	}
 }
+
+// weightedPathLength 返回树的带权路径长度
+func weightedPathLength(tree Node) float64 {
+	tree.GenerateCode()
+	var wpl float64
+	tree.TraversalLeaf(func(leaf Leaf) {
+		// -1 是因为要去掉开头占位的 “1”
+		wpl += leaf.Value() * float64(bits.Len64(leaf.Code())-1)
+	})
+	return wpl
+}
+
+func TestCreateTreeWithFibHeap(t *testing.T) {
+	l := []Element{
+		&element{c: 'A', w: 3},
+		&element{c: 'B', w: 1},
+		&element{c: 'C', w: 2},
+		&element{c: 'D', w: 1},
+		&element{c: 'E', w: 8},
+		&element{c: 'F', w: 5},
+		&element{c: 'G', w: 13},
+	}
+
+	want := CreateHuffmanTree(l...)
+	got := CreateHuffmanTreeWith(heap.NewFibHeap(heap.MinHeap), l...)
+	if w, g := weightedPathLength(want), weightedPathLength(got); g != w {
+		t.Fatalf("weighted path length: %f --> %f", w, g)
+	}
+
+	chars := make(map[interface{}]bool)
+	got.TraversalLeaf(func(leaf Leaf) {
+		chars[leaf.Char()] = true
+	})
+	if len(chars) != len(l) {
+		t.Fatalf("leaves: %d --> %d", len(l), len(chars))
+	}
+}